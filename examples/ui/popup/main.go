@@ -30,6 +30,10 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
+	err = scene.Layer(ui.PopupLayer).LoadGoml("popup.goml")
+	if err != nil {
+		panic(err)
+	}
 
 	opened := true
 
@@ -0,0 +1,247 @@
+// Package cpu brings software rasterizer that can stand in for ggl.Canvas where there is no
+// opengl context, mainly in tests and on CI machines. Canvas implements both ggl.Target and
+// ggl.Renderer so anything that can be drawn to Batch or Window can be drawn to it as well.
+// Drawing is slow compared to gpu so do not use it for real time rendering.
+package cpu
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/jakubDoka/mlok/ggl"
+	"github.com/jakubDoka/mlok/mat"
+)
+
+// Canvas is software counterpart of ggl.Canvas. Triangles are rasterized into Pic with same
+// semantics as ggl.Setup2D shaders have, that means Vertex.Color and Vertex.Intensity are
+// treated the same way and blending is the same as Setup2D.Modify sets up. Coordinates also
+// match, center of the Pic is the origin and y axis points up, though unlike texture captured
+// from gpu, Pic is not upside down.
+//
+// Texture is sampled with nearest filter and repeated as with ggl.DefaultTextureConfig. Row 0
+// of the Texture corresponds to texture coordinate 0, so it should be same image you would pass
+// to ggl.NTexture with flip set to false. Texture can be nil, in that case textured triangles are
+// drawn black, as they would be if Batch had no texture.
+type Canvas struct {
+	Pic     *image.NRGBA
+	Texture image.Image
+
+	mat mat.Mat
+}
+
+// NCanvas creates canvas of given size that samples given texture
+func NCanvas(w, h int, texture image.Image) *Canvas {
+	return &Canvas{
+		Pic:     image.NewNRGBA(image.Rect(0, 0, w, h)),
+		Texture: texture,
+		mat:     mat.IM,
+	}
+}
+
+// Frame returns canvas frame relative to its center, same as ggl.Canvas.Frame
+func (c *Canvas) Frame() mat.AABB {
+	f := mat.FromRect(c.Pic.Rect)
+	return f.Moved(f.Center().Inv())
+}
+
+// SetCamera sets view matrix that all triangles are projected by
+func (c *Canvas) SetCamera(mat mat.Mat) {
+	c.mat = mat
+}
+
+// Resize resizes the canvas, resizing does not maintain canvas content.
+// If canvas is already in given size, this function does nothing
+func (c *Canvas) Resize(w, h int) {
+	if c.Pic.Rect.Dx() == w && c.Pic.Rect.Dy() == h {
+		return
+	}
+	c.Pic = image.NewNRGBA(image.Rect(0, 0, w, h))
+}
+
+// Clear fills whole canvas with color
+func (c *Canvas) Clear(color mat.RGBA) {
+	col := toNRGBA(color)
+	for i := 0; i < len(c.Pic.Pix); i += 4 {
+		c.Pic.Pix[i] = col.R
+		c.Pic.Pix[i+1] = col.G
+		c.Pic.Pix[i+2] = col.B
+		c.Pic.Pix[i+3] = col.A
+	}
+}
+
+// Image returns copy of canvas content
+func (c *Canvas) Image() *image.NRGBA {
+	img := image.NewNRGBA(c.Pic.Rect)
+	copy(img.Pix, c.Pic.Pix)
+	return img
+}
+
+// Accept implements ggl.Target interface, triangles are rasterized right away
+func (c *Canvas) Accept(vertexes ggl.Vertexes, indices ggl.Indices) {
+	if len(indices) == 0 {
+		for i := 0; i+2 < len(vertexes); i += 3 {
+			c.Triangle(vertexes[i], vertexes[i+1], vertexes[i+2])
+		}
+		return
+	}
+
+	for i := 0; i+2 < len(indices); i += 3 {
+		c.Triangle(vertexes[indices[i]], vertexes[indices[i+1]], vertexes[indices[i+2]])
+	}
+}
+
+// Render implements ggl.Renderer interface, texture, program and buffer are ignored as they
+// are gpu objects, Canvas.Texture is used instead.
+//
+// panics if data is not ggl.Vertexes
+func (c *Canvas) Render(data ggl.VertexData, indices ggl.Indices, texture *ggl.Texture, program *ggl.Program, buffer *ggl.Buffer) {
+	vertexes, ok := data.(ggl.Vertexes)
+	if !ok {
+		panic(fmt.Errorf("unexpected vertex data type, canvas expects %T, but %T was inputted", vertexes, data))
+	}
+
+	c.Accept(vertexes, indices)
+}
+
+// Triangle rasterizes one triangle, vertex attributes are interpolated
+// and result is blended with canvas content
+func (c *Canvas) Triangle(a, b, d ggl.Vertex) {
+	p0, p1, p2 := c.project(a.Pos), c.project(b.Pos), c.project(d.Pos)
+
+	area := edge(p0, p1, p2)
+	if area == 0 {
+		return
+	}
+	if area < 0 { // there is no culling so winding is just normalized
+		p1, p2 = p2, p1
+		b, d = d, b
+		area = -area
+	}
+
+	bounds := mat.VecBounds(p0, p1, p2).Intersect(mat.FromRect(c.Pic.Rect))
+	minX, minY := int(math.Floor(bounds.Min.X)), int(math.Floor(bounds.Min.Y))
+	maxX, maxY := int(math.Ceil(bounds.Max.X)), int(math.Ceil(bounds.Max.Y))
+
+	for y := minY; y < maxY; y++ {
+		for x := minX; x < maxX; x++ {
+			p := mat.V(float64(x)+.5, float64(y)+.5)
+
+			w0, w1, w2 := edge(p1, p2, p), edge(p2, p0, p), edge(p0, p1, p)
+			if !covers(w0, p1, p2) || !covers(w1, p2, p0) || !covers(w2, p0, p1) {
+				continue
+			}
+
+			// interpolating relative to first vertex keeps constant attributes exact
+			w1, w2 = w1/area, w2/area
+			frag := ggl.Vertex{
+				Tex:       a.Tex.Add(a.Tex.To(b.Tex).Scaled(w1)).Add(a.Tex.To(d.Tex).Scaled(w2)),
+				Color:     a.Color.Add(b.Color.Sub(a.Color).Scaled(w1)).Add(d.Color.Sub(a.Color).Scaled(w2)),
+				Intensity: a.Intensity + (b.Intensity-a.Intensity)*w1 + (d.Intensity-a.Intensity)*w2,
+			}
+
+			c.blend(x, y, c.Fragment(frag))
+		}
+	}
+}
+
+// Fragment computes color of a fragment the same way ggl.Setup2D fragment shader does
+func (c *Canvas) Fragment(v ggl.Vertex) mat.RGBA {
+	switch v.Intensity {
+	case 1:
+		return c.Sample(v.Tex).Mul(v.Color)
+	case 0:
+		return v.Color
+	default:
+		col := c.Sample(v.Tex)
+		return col.Add(mat.Alpha(1).Sub(col).Scaled(1 - v.Intensity))
+	}
+}
+
+// Sample returns texture color at given texture coordinate, color is not alpha-premultiplied
+func (c *Canvas) Sample(tex mat.Vec) mat.RGBA {
+	if c.Texture == nil {
+		return mat.Black
+	}
+
+	b := c.Texture.Bounds()
+	if b.Empty() {
+		return mat.Black
+	}
+
+	x := b.Min.X + repeat(int(math.Floor(tex.X)), b.Dx())
+	y := b.Min.Y + repeat(int(math.Floor(tex.Y)), b.Dy())
+
+	var col color.NRGBA
+	if img, ok := c.Texture.(*image.NRGBA); ok {
+		col = img.NRGBAAt(x, y)
+	} else {
+		col = color.NRGBAModel.Convert(c.Texture.At(x, y)).(color.NRGBA)
+	}
+
+	return mat.RGBA{
+		R: float64(col.R) / 0xff,
+		G: float64(col.G) / 0xff,
+		B: float64(col.B) / 0xff,
+		A: float64(col.A) / 0xff,
+	}
+}
+
+// blend blends color to pixel as gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA) would
+func (c *Canvas) blend(x, y int, src mat.RGBA) {
+	i := c.Pic.PixOffset(x, y)
+	px := c.Pic.Pix[i : i+4 : i+4]
+
+	src = clamp(src)
+	inv := 1 - src.A
+	for j, v := range src.Flatten() {
+		px[j] = toByte(v*src.A + float64(px[j])/0xff*inv)
+	}
+}
+
+// project turns world coordinate into pixel coordinate
+func (c *Canvas) project(pos mat.Vec) mat.Vec {
+	pos = c.mat.Project(pos)
+	size := mat.FromRect(c.Pic.Rect).Size().Scaled(.5)
+	return mat.V(pos.X+size.X, size.Y-pos.Y)
+}
+
+// edge is positive if c is on the right side of a -> b in pixel coordinates
+func edge(a, b, c mat.Vec) float64 {
+	return (c.X-a.X)*(b.Y-a.Y) - (c.Y-a.Y)*(b.X-a.X)
+}
+
+// covers applies top-left rule so pixels on shared edges are not drawn twice
+func covers(w float64, a, b mat.Vec) bool {
+	if w != 0 {
+		return w > 0
+	}
+	d := a.To(b)
+	return d.Y > 0 || d.Y == 0 && d.X < 0
+}
+
+func repeat(v, size int) int {
+	v %= size
+	if v < 0 {
+		v += size
+	}
+	return v
+}
+
+func clamp(c mat.RGBA) mat.RGBA {
+	return mat.RGBA{
+		R: mat.Clamp(c.R, 0, 1),
+		G: mat.Clamp(c.G, 0, 1),
+		B: mat.Clamp(c.B, 0, 1),
+		A: mat.Clamp(c.A, 0, 1),
+	}
+}
+
+func toByte(v float64) uint8 {
+	return uint8(mat.Clamp(v, 0, 1)*0xff + .5)
+}
+
+func toNRGBA(c mat.RGBA) color.NRGBA {
+	return color.NRGBA{R: toByte(c.R), G: toByte(c.G), B: toByte(c.B), A: toByte(c.A)}
+}
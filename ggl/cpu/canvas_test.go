@@ -0,0 +1,90 @@
+package cpu
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/jakubDoka/mlok/ggl"
+	"github.com/jakubDoka/mlok/mat"
+)
+
+func TestCanvas(t *testing.T) {
+	tex := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	tex.SetNRGBA(0, 0, color.NRGBA{R: 0xff, A: 0xff})
+	tex.SetNRGBA(1, 0, color.NRGBA{G: 0xff, A: 0xff})
+	tex.SetNRGBA(0, 1, color.NRGBA{B: 0xff, A: 0xff})
+	tex.SetNRGBA(1, 1, color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff})
+
+	var (
+		red   = color.NRGBA{R: 0xff, A: 0xff}
+		green = color.NRGBA{G: 0xff, A: 0xff}
+		blue  = color.NRGBA{B: 0xff, A: 0xff}
+		white = color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+		none  = color.NRGBA{}
+	)
+
+	testCases := []struct {
+		desc string
+		draw func(c *Canvas)
+		res  [4][4]color.NRGBA
+	}{
+		{
+			desc: "solid",
+			draw: func(c *Canvas) {
+				s := ggl.NSprite(mat.A(0, 0, 2, 2))
+				s.SetIntensity(0)
+				s.Draw(c, mat.IM, mat.Red)
+			},
+			res: [4][4]color.NRGBA{
+				{none, none, none, none},
+				{none, red, red, none},
+				{none, red, red, none},
+				{none, none, none, none},
+			},
+		},
+		{
+			desc: "textured",
+			draw: func(c *Canvas) {
+				s := ggl.NSprite(mat.A(0, 0, 2, 2))
+				s.Draw(c, mat.IM.Scaled(mat.ZV, 2), mat.White)
+			},
+			res: [4][4]color.NRGBA{
+				{blue, blue, white, white},
+				{blue, blue, white, white},
+				{red, red, green, green},
+				{red, red, green, green},
+			},
+		},
+		{
+			desc: "blended",
+			draw: func(c *Canvas) {
+				c.Clear(mat.White)
+				s := ggl.NSprite(mat.A(0, 0, 4, 4))
+				s.SetIntensity(0)
+				s.Draw(c, mat.IM, mat.RGBA{R: 1, A: .5})
+			},
+			res: func() (res [4][4]color.NRGBA) {
+				for y := range res {
+					for x := range res[y] {
+						res[y][x] = color.NRGBA{R: 0xff, G: 0x80, B: 0x80, A: 0xbf}
+					}
+				}
+				return
+			}(),
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			c := NCanvas(4, 4, tex)
+			tC.draw(c)
+			for y := range tC.res {
+				for x, e := range tC.res[y] {
+					if px := c.Pic.NRGBAAt(x, y); px != e {
+						t.Errorf("\n%d %d\n%v\n%v", x, y, px, e)
+					}
+				}
+			}
+		})
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/jakubDoka/mlok/ggl"
+	"github.com/jakubDoka/mlok/ggl/golden"
+	"github.com/jakubDoka/mlok/mat"
 )
 
 func TestCircle(t *testing.T) {
@@ -14,3 +16,11 @@ func TestCircle(t *testing.T) {
 		t.Errorf("\n%#v\n%#v", c.Indices, r)
 	}
 }
+
+func TestGeomGolden(t *testing.T) {
+	g := NGeomDrawer()
+	g.Color(mat.Red).Circle(mat.C(-20, 0, 20))
+	g.Color(mat.Green).Fill(false).Thickness(4).Circle(mat.C(20, 0, 20))
+	g.Color(mat.RGBA{B: 1, A: .5}).Fill(true).AABB(mat.A(-30, -30, 30, -10))
+	golden.Default.Assert(t, "geom", &g)
+}
@@ -1,6 +1,7 @@
 package particle
 
 import (
+	"io/ioutil"
 	"math"
 	"sync"
 
@@ -20,6 +21,8 @@ type Parser struct {
 
 	Parser goss.Parser
 	Styles goss.Styles
+
+	constructed map[string][]*Type
 }
 
 // Construct constructs the Type under given name and returns it
@@ -33,9 +36,62 @@ func (p *Parser) Construct(name string) *Type {
 
 	t.Parse(WrapStyle(stl), &p.Assets)
 
+	if p.constructed == nil {
+		p.constructed = map[string][]*Type{}
+	}
+	p.constructed[name] = append(p.constructed[name], &t)
+
 	return &t
 }
 
+// Reload parses all Types constructed by parser again, Types are updated in place so
+// particles that use them change too
+func (p *Parser) Reload() {
+	for name, types := range p.constructed {
+		stl, ok := p.Styles[name]
+		if !ok {
+			continue
+		}
+		for _, t := range types {
+			t.Parse(WrapStyle(stl), &p.Assets)
+			if t.threadCount != 0 {
+				t.setThreads(t.threadCount)
+			}
+		}
+	}
+}
+
+// LoadGoss loads goss files and adds them to Styles
+func (p *Parser) LoadGoss(paths ...string) error {
+	for _, path := range paths {
+		bts, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if err := p.AddGoss(bts); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Watch makes watcher reload given goss files when they change, constructed Types are
+// then updated by Reload, this is handy for tweaking particles while game is running
+//
+//	parser.Watch(scene.Watcher(), "particles.goss")
+func (p *Parser) Watch(w *load.Watcher, paths ...string) {
+	for _, path := range paths {
+		w.Watch(path, func(path string) error {
+			if err := p.LoadGoss(path); err != nil {
+				return err
+			}
+			p.Reload()
+			return nil
+		})
+	}
+}
+
 // AddGoss parses the goss source and adds all results to Styles
 func (p *Parser) AddGoss(source []byte) error {
 	stl, err := p.Parser.Parse(source)
@@ -0,0 +1,229 @@
+// Package golden offers helpers for snapshot testing of anything that can be drawn. Fetcher
+// is rendered with cpu.Canvas and result is compared with png stored in test directory.
+// When comparison fails, actual image and diff image are written next to the expected one
+// so you can see what changed. Set GOLDEN_UPDATE environment variable to rewrite expected
+// images:
+//
+//	GOLDEN_UPDATE=1 go test ./...
+//
+// Simple test can look like:
+//
+//	func TestCircle(t *testing.T) {
+//		g := drw.NGeomDrawer()
+//		g.Circle(mat.C(0, 0, 30))
+//		golden.Default.Assert(t, "circle", &g)
+//	}
+package golden
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jakubDoka/mlok/ggl"
+	"github.com/jakubDoka/mlok/ggl/cpu"
+	"github.com/jakubDoka/mlok/mat"
+)
+
+// Update is true when GOLDEN_UPDATE environment variable is not empty, expected images are
+// then rewritten instead of compared. Flag would have to be defined in every tested package.
+var Update = os.Getenv("GOLDEN_UPDATE") != ""
+
+// file suffixes
+const (
+	Ext       = ".png"
+	ActualExt = ".actual.png"
+	DiffExt   = ".diff.png"
+)
+
+// Default is config used by most tests, images are stored in test_data as is convention in
+// this repository
+var Default = Config{
+	Width:     100,
+	Height:    100,
+	Camera:    mat.IM,
+	Tolerance: 2,
+	Dir:       "test_data",
+}
+
+// Config holds settings for rendering and comparison
+type Config struct {
+	// size of rendered image
+	Width, Height int
+	// texture used by cpu.Canvas, can be nil
+	Texture image.Image
+	// camera used by cpu.Canvas, zero value is treated as mat.IM
+	Camera mat.Mat
+	// color the canvas is cleared with
+	Background mat.RGBA
+	// maximal difference of each color channel that is still considered equal
+	Tolerance uint8
+	// directory with expected images
+	Dir string
+}
+
+// Render draws fetcher to new canvas and returns the result
+func (c Config) Render(f ggl.Fetcher) *image.NRGBA {
+	cv := cpu.NCanvas(c.Width, c.Height, c.Texture)
+	if c.Camera != (mat.Mat{}) {
+		cv.SetCamera(c.Camera)
+	}
+	cv.Clear(c.Background)
+	f.Fetch(cv)
+	return cv.Pic
+}
+
+// Assert renders fetcher and compares it with expected image of given name
+func (c Config) Assert(t testing.TB, name string, f ggl.Fetcher) {
+	t.Helper()
+	c.AssertImage(t, name, c.Render(f))
+}
+
+// AssertImage compares image with expected image of given name, if they differ test fails
+// and actual image with diff image are saved to Config.Dir. If Update is true image is
+// just saved as expected one.
+func (c Config) AssertImage(t testing.TB, name string, img *image.NRGBA) {
+	t.Helper()
+
+	path := filepath.Join(c.Dir, name)
+
+	if Update {
+		if err := os.MkdirAll(c.Dir, os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err := Save(path+Ext, img); err != nil {
+			t.Fatal(err)
+		}
+		os.Remove(path + ActualExt)
+		os.Remove(path + DiffExt)
+		return
+	}
+
+	expected, err := Load(path + Ext)
+	if err != nil {
+		t.Fatalf("%v (run tests with GOLDEN_UPDATE=1 to create it)", err)
+	}
+
+	diff, count := Diff(expected, img, c.Tolerance)
+	if count == 0 {
+		os.Remove(path + ActualExt)
+		os.Remove(path + DiffExt)
+		return
+	}
+
+	if err := Save(path+ActualExt, img); err != nil {
+		t.Error(err)
+	}
+	if err := Save(path+DiffExt, diff); err != nil {
+		t.Error(err)
+	}
+
+	if expected.Rect.Size() != img.Rect.Size() {
+		t.Errorf("%s: size mismatch, expected %v but got %v", name, expected.Rect.Size(), img.Rect.Size())
+	} else {
+		t.Errorf("%s: %d pixels differ, see %s", name, count, path+DiffExt)
+	}
+}
+
+// Diff compares two images and returns number of pixels that differ by more then tolerance in
+// any channel. Returned diff image has differing pixels red and others are faded version of a.
+// Images of different size are compared on the union of their bounds and pixels outside
+// one of images always differ.
+func Diff(a, b *image.NRGBA, tolerance uint8) (diff *image.NRGBA, count int) {
+	as, bs := a.Rect.Size(), b.Rect.Size()
+	bounds := image.Rectangle{Max: as}.Union(image.Rectangle{Max: bs})
+	diff = image.NewNRGBA(bounds)
+
+	inside := func(p, size image.Point) bool {
+		return p.X < size.X && p.Y < size.Y
+	}
+
+	for y := 0; y < bounds.Max.Y; y++ {
+		for x := 0; x < bounds.Max.X; x++ {
+			p := image.Pt(x, y)
+			if !inside(p, as) || !inside(p, bs) {
+				diff.SetNRGBA(x, y, Mismatch)
+				count++
+				continue
+			}
+
+			ac := a.NRGBAAt(a.Rect.Min.X+x, a.Rect.Min.Y+y)
+			bc := b.NRGBAAt(b.Rect.Min.X+x, b.Rect.Min.Y+y)
+			if !Equal(ac, bc, tolerance) {
+				diff.SetNRGBA(x, y, Mismatch)
+				count++
+				continue
+			}
+
+			ac.A /= 4
+			diff.SetNRGBA(x, y, ac)
+		}
+	}
+
+	return
+}
+
+// Mismatch is color of differing pixels in diff image
+var Mismatch = color.NRGBA{R: 0xff, A: 0xff}
+
+// Equal reports whether colors do not differ by more then tolerance in any channel
+func Equal(a, b color.NRGBA, tolerance uint8) bool {
+	return delta(a.R, b.R) <= tolerance &&
+		delta(a.G, b.G) <= tolerance &&
+		delta(a.B, b.B) <= tolerance &&
+		delta(a.A, b.A) <= tolerance
+}
+
+// Load loads png from disk as NRGBA
+func Load(path string) (*image.NRGBA, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	img, err := png.Decode(f)
+	if err != nil {
+		return nil, err
+	}
+
+	if nrgba, ok := img.(*image.NRGBA); ok {
+		return nrgba, nil
+	}
+
+	b := img.Bounds()
+	nrgba := image.NewNRGBA(b)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			nrgba.Set(x, y, img.At(x, y))
+		}
+	}
+
+	return nrgba, nil
+}
+
+// Save saves image as png
+func Save(path string, img image.Image) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	err = png.Encode(f, img)
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+func delta(a, b uint8) uint8 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
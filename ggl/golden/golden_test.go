@@ -0,0 +1,76 @@
+package golden
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	a := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	a.SetNRGBA(0, 0, color.NRGBA{R: 10, A: 0xff})
+
+	testCases := []struct {
+		desc      string
+		b         func() *image.NRGBA
+		tolerance uint8
+		count     int
+	}{
+		{
+			desc: "same",
+			b: func() *image.NRGBA {
+				b := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+				b.SetNRGBA(0, 0, color.NRGBA{A: 0xff})
+				return b
+			},
+			tolerance: 10,
+			count:     0,
+		},
+		{
+			desc: "over tolerance",
+			b: func() *image.NRGBA {
+				b := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+				b.SetNRGBA(0, 0, color.NRGBA{A: 0xff})
+				return b
+			},
+			tolerance: 9,
+			count:     1,
+		},
+		{
+			desc: "offset bounds",
+			b: func() *image.NRGBA {
+				b := image.NewNRGBA(image.Rect(5, 5, 7, 7))
+				b.SetNRGBA(5, 5, color.NRGBA{R: 10, A: 0xff})
+				return b
+			},
+			count: 0,
+		},
+		{
+			desc: "size",
+			b: func() *image.NRGBA {
+				b := image.NewNRGBA(image.Rect(0, 0, 3, 2))
+				b.SetNRGBA(0, 0, color.NRGBA{R: 10, A: 0xff})
+				return b
+			},
+			count: 2,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			diff, count := Diff(a, tC.b(), tC.tolerance)
+			if count != tC.count {
+				t.Errorf("\n%v\n%v", count, tC.count)
+			}
+
+			red := 0
+			for i := 0; i < len(diff.Pix); i += 4 {
+				if diff.NRGBAAt(i/4%diff.Rect.Dx(), i/4/diff.Rect.Dx()) == Mismatch {
+					red++
+				}
+			}
+			if red != count {
+				t.Errorf("\n%v\n%v", red, count)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package input
+
+import (
+	"math"
+
+	"github.com/jakubDoka/mlok/ggl/key"
+)
+
+// Gamepad is state of one gamepad in standard layout
+type Gamepad struct {
+	Connected bool
+	Buttons   [key.GamepadLast + 1]bool
+	Axes      [key.AxisLast + 1]float64
+}
+
+// GamepadSource provides states of gamepads, ggl.Window polls it each update. Source can
+// be replaced so gamepads can be simulated without hardware.
+type GamepadSource interface {
+	Gamepad(j key.Joystick) Gamepad
+}
+
+// GamepadFunc is function that implements GamepadSource
+type GamepadFunc func(j key.Joystick) Gamepad
+
+// Gamepad implements GamepadSource interface
+func (f GamepadFunc) Gamepad(j key.Joystick) Gamepad {
+	return f(j)
+}
+
+// Poll fills gamepads of state from source
+func Poll(src GamepadSource, s *State) {
+	for j := range s.Gamepads {
+		s.Gamepads[j] = src.Gamepad(key.Joystick(j))
+	}
+}
+
+// Mapping maps raw joystick buttons and axes to standard layout, it is used for joysticks
+// that have no known standard mapping. Values are indexes of raw buttons and axes, negative
+// index means that standard button or axis is not mapped.
+type Mapping struct {
+	Buttons [key.GamepadLast + 1]int
+	Axes    [key.AxisLast + 1]int
+	// Invert marks axes that should be inverted
+	Invert [key.AxisLast + 1]bool
+}
+
+// StandardMapping maps raw buttons and axes in the same order as standard layout
+var StandardMapping = func() (m Mapping) {
+	for i := range m.Buttons {
+		m.Buttons[i] = i
+	}
+	for i := range m.Axes {
+		m.Axes[i] = i
+	}
+	return
+}()
+
+// Map creates gamepad state from raw joystick state, missing buttons and axes are
+// left released
+func (m *Mapping) Map(buttons []bool, axes []float64) (g Gamepad) {
+	g.Connected = true
+	for i, idx := range m.Buttons {
+		if idx >= 0 && idx < len(buttons) {
+			g.Buttons[i] = buttons[idx]
+		}
+	}
+	for i, idx := range m.Axes {
+		if idx >= 0 && idx < len(axes) {
+			g.Axes[i] = axes[idx]
+			if m.Invert[i] {
+				g.Axes[i] = -g.Axes[i]
+			}
+		}
+	}
+	return
+}
+
+// Deadzone returns 0 if absolute value is smaller then deadzone, otherwise value is
+// rescaled so output still goes smoothly from 0 to 1
+func Deadzone(value, deadzone float64) float64 {
+	abs := math.Abs(value)
+	if abs <= deadzone || deadzone >= 1 {
+		return 0
+	}
+	return math.Copysign(math.Min((abs-deadzone)/(1-deadzone), 1), value)
+}
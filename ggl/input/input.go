@@ -0,0 +1,253 @@
+// Package input abstracts user input away from the window so logic that reacts to input
+// can be driven by anything that implements Input. ggl.Window is the real source, Fake can
+// be scripted from tests and Replay plays back input captured by Recorder.
+package input
+
+import (
+	"github.com/jakubDoka/mlok/ggl/key"
+	"github.com/jakubDoka/mlok/mat"
+)
+
+// Input is state of keyboard and mouse in current frame, all methods should return values
+// consistent until next frame
+type Input interface {
+	// Pressed returns whether the Button is currently pressed down.
+	Pressed(button key.Key) bool
+	// JustPressed returns whether the Button has just been pressed down.
+	JustPressed(button key.Key) bool
+	// JustReleased returns whether the Button has just been released up.
+	JustReleased(button key.Key) bool
+	// Repeated returns whether a repeat event has been triggered on button.
+	Repeated(button key.Key) bool
+	// MousePos returns the current mouse position.
+	MousePos() mat.Vec
+	// MousePrevPos returns the mouse position from previous frame.
+	MousePrevPos() mat.Vec
+	// MouseIsInside returns true if the mouse is inside the window.
+	MouseIsInside() bool
+	// MouseScroll returns the mouse scroll amount (in both axes) since the last frame.
+	MouseScroll() mat.Vec
+	// Typed returns the text typed on the keyboard since the last frame.
+	Typed() string
+
+	// GamepadConnected returns whether gamepad is connected.
+	GamepadConnected(j key.Joystick) bool
+	// GamepadPressed returns whether gamepad button is currently pressed down.
+	GamepadPressed(j key.Joystick, button key.GamepadButton) bool
+	// GamepadJustPressed returns whether gamepad button has just been pressed down.
+	GamepadJustPressed(j key.Joystick, button key.GamepadButton) bool
+	// GamepadJustReleased returns whether gamepad button has just been released up.
+	GamepadJustReleased(j key.Joystick, button key.GamepadButton) bool
+	// GamepadAxis returns value of gamepad axis, no deadzone is applied.
+	GamepadAxis(j key.Joystick, axis key.Axis) float64
+}
+
+// State is snapshot of input in one frame
+type State struct {
+	Mouse   mat.Vec
+	Buttons [key.Last + 1]bool
+	Repeat  [key.Last + 1]bool
+	Scroll  mat.Vec
+	Typed   string
+	Inside  bool
+
+	Gamepads [key.JoystickLast + 1]Gamepad
+}
+
+// Next returns state that follows s, buttons, mouse and gamepads stay, events are cleared
+func (s State) Next() State {
+	s.Repeat = [key.Last + 1]bool{}
+	s.Scroll = mat.Vec{}
+	s.Typed = ""
+	return s
+}
+
+// Frames implements Input on top of two consecutive states
+type Frames struct {
+	Prev, Curr State
+}
+
+// Push makes s the current state
+func (f *Frames) Push(s State) {
+	f.Prev = f.Curr
+	f.Curr = s
+}
+
+// Pressed implements Input interface
+func (f *Frames) Pressed(button key.Key) bool {
+	return valid(button) && f.Curr.Buttons[button]
+}
+
+// JustPressed implements Input interface
+func (f *Frames) JustPressed(button key.Key) bool {
+	return valid(button) && f.Curr.Buttons[button] && !f.Prev.Buttons[button]
+}
+
+// JustReleased implements Input interface
+func (f *Frames) JustReleased(button key.Key) bool {
+	return valid(button) && !f.Curr.Buttons[button] && f.Prev.Buttons[button]
+}
+
+// Repeated implements Input interface
+func (f *Frames) Repeated(button key.Key) bool {
+	return valid(button) && f.Curr.Repeat[button]
+}
+
+// MousePos implements Input interface
+func (f *Frames) MousePos() mat.Vec {
+	return f.Curr.Mouse
+}
+
+// MousePrevPos implements Input interface
+func (f *Frames) MousePrevPos() mat.Vec {
+	return f.Prev.Mouse
+}
+
+// MouseIsInside implements Input interface
+func (f *Frames) MouseIsInside() bool {
+	return f.Curr.Inside
+}
+
+// MouseScroll implements Input interface
+func (f *Frames) MouseScroll() mat.Vec {
+	return f.Curr.Scroll
+}
+
+// Typed implements Input interface
+func (f *Frames) Typed() string {
+	return f.Curr.Typed
+}
+
+// GamepadConnected implements Input interface
+func (f *Frames) GamepadConnected(j key.Joystick) bool {
+	return validJoystick(j) && f.Curr.Gamepads[j].Connected
+}
+
+// GamepadPressed implements Input interface
+func (f *Frames) GamepadPressed(j key.Joystick, button key.GamepadButton) bool {
+	return validGamepad(j, button) && f.Curr.Gamepads[j].Buttons[button]
+}
+
+// GamepadJustPressed implements Input interface
+func (f *Frames) GamepadJustPressed(j key.Joystick, button key.GamepadButton) bool {
+	return validGamepad(j, button) && f.Curr.Gamepads[j].Buttons[button] && !f.Prev.Gamepads[j].Buttons[button]
+}
+
+// GamepadJustReleased implements Input interface
+func (f *Frames) GamepadJustReleased(j key.Joystick, button key.GamepadButton) bool {
+	return validGamepad(j, button) && !f.Curr.Gamepads[j].Buttons[button] && f.Prev.Gamepads[j].Buttons[button]
+}
+
+// GamepadAxis implements Input interface
+func (f *Frames) GamepadAxis(j key.Joystick, axis key.Axis) float64 {
+	if !validJoystick(j) || axis < 0 || axis > key.AxisLast {
+		return 0
+	}
+	return f.Curr.Gamepads[j].Axes[axis]
+}
+
+// Fake is Input you can script, you modify Next and then call Update to make it current,
+// just like window does it with events. Fake is meant for tests:
+//
+//	var f input.Fake
+//	f.Move(mat.V(10, 10))
+//	f.Press(key.MouseLeft)
+//	f.Update()
+//	processor.Update(&f, 1) // element at 10, 10 gets clicked
+type Fake struct {
+	Frames
+	Next State
+	// Gamepads is optional source polled on Update
+	Gamepads GamepadSource
+}
+
+// Update makes Next the current state, Next keeps buttons and mouse but events are cleared
+func (f *Fake) Update() {
+	if f.Gamepads != nil {
+		Poll(f.Gamepads, &f.Next)
+	}
+	f.Push(f.Next)
+	f.Next = f.Next.Next()
+}
+
+// Press presses buttons in next frame
+func (f *Fake) Press(buttons ...key.Key) {
+	for _, b := range buttons {
+		if valid(b) {
+			f.Next.Buttons[b] = true
+		}
+	}
+}
+
+// Release releases buttons in next frame
+func (f *Fake) Release(buttons ...key.Key) {
+	for _, b := range buttons {
+		if valid(b) {
+			f.Next.Buttons[b] = false
+		}
+	}
+}
+
+// Repeat triggers repeat event of buttons in next frame
+func (f *Fake) Repeat(buttons ...key.Key) {
+	for _, b := range buttons {
+		if valid(b) {
+			f.Next.Repeat[b] = true
+		}
+	}
+}
+
+// Move moves mouse in next frame, mouse is also considered inside the window
+func (f *Fake) Move(pos mat.Vec) {
+	f.Next.Mouse = pos
+	f.Next.Inside = true
+}
+
+// Scroll adds scroll to next frame
+func (f *Fake) Scroll(amount mat.Vec) {
+	f.Next.Scroll = f.Next.Scroll.Add(amount)
+}
+
+// Type adds typed text to next frame
+func (f *Fake) Type(text string) {
+	f.Next.Typed += text
+}
+
+// PressGamepad presses gamepad buttons in next frame, gamepad gets connected
+func (f *Fake) PressGamepad(j key.Joystick, buttons ...key.GamepadButton) {
+	f.setGamepad(j, buttons, true)
+}
+
+// ReleaseGamepad releases gamepad buttons in next frame
+func (f *Fake) ReleaseGamepad(j key.Joystick, buttons ...key.GamepadButton) {
+	f.setGamepad(j, buttons, false)
+}
+
+// MoveAxis sets gamepad axis in next frame, gamepad gets connected
+func (f *Fake) MoveAxis(j key.Joystick, axis key.Axis, value float64) {
+	if validJoystick(j) && axis >= 0 && axis <= key.AxisLast {
+		f.Next.Gamepads[j].Connected = true
+		f.Next.Gamepads[j].Axes[axis] = value
+	}
+}
+
+func (f *Fake) setGamepad(j key.Joystick, buttons []key.GamepadButton, value bool) {
+	for _, b := range buttons {
+		if validGamepad(j, b) {
+			f.Next.Gamepads[j].Connected = true
+			f.Next.Gamepads[j].Buttons[b] = value
+		}
+	}
+}
+
+func valid(button key.Key) bool {
+	return button >= 0 && button <= key.Last
+}
+
+func validJoystick(j key.Joystick) bool {
+	return j >= 0 && j <= key.JoystickLast
+}
+
+func validGamepad(j key.Joystick, button key.GamepadButton) bool {
+	return validJoystick(j) && button >= 0 && button <= key.GamepadLast
+}
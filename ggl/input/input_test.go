@@ -0,0 +1,109 @@
+package input
+
+import (
+	"testing"
+
+	"github.com/jakubDoka/mlok/ggl/key"
+	"github.com/jakubDoka/mlok/mat"
+)
+
+func TestFake(t *testing.T) {
+	var f Fake
+	f.Press(key.A)
+	f.Move(mat.V(10, 20))
+	f.Type("a")
+	f.Update()
+
+	if !f.JustPressed(key.A) || !f.Pressed(key.A) || f.Typed() != "a" || f.MousePos() != mat.V(10, 20) {
+		t.Errorf("\n%v\n%v", f.Curr.Typed, f.MousePos())
+	}
+
+	f.Release(key.A)
+	f.Update()
+
+	if !f.JustReleased(key.A) || f.Pressed(key.A) || f.Typed() != "" || f.MousePrevPos() != mat.V(10, 20) {
+		t.Errorf("\n%v\n%v", f.Pressed(key.A), f.Typed())
+	}
+
+	if f.Pressed(key.Unknown) || f.JustPressed(key.Last+1) {
+		t.Error("invalid key reported as pressed")
+	}
+}
+
+func TestRecord(t *testing.T) {
+	var f Fake
+	rec := NRecorder(&f)
+
+	frames := []func(){
+		func() { f.Press(key.W, key.MouseLeft); f.Move(mat.V(1, 2)) },
+		func() { f.Repeat(key.W); f.Type("hello"); f.Scroll(mat.V(0, 3)) },
+		func() { f.Release(key.W) },
+	}
+
+	var states []State
+	for i, fr := range frames {
+		fr()
+		f.Update()
+		rec.Record(float64(i))
+		states = append(states, f.Curr)
+	}
+
+	rep, err := NReplay(rec.Buffer.Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, s := range states {
+		delta, ok := rep.Next()
+		if !ok || delta != float64(i) {
+			t.Fatalf("\n%v\n%v", ok, delta)
+		}
+		if rep.Curr != s {
+			t.Errorf("frame %d\n%v\n%v", i, rep.Curr.Typed, s.Typed)
+		}
+	}
+
+	if _, ok := rep.Next(); ok {
+		t.Error("replay should be finished")
+	}
+
+	if _, err := NReplay([]byte{1}); err == nil {
+		t.Error("corrupted recording should fail")
+	}
+}
+
+func TestGamepad(t *testing.T) {
+	m := StandardMapping
+	m.Buttons[key.GamepadA] = 3
+	m.Buttons[key.GamepadB] = -1
+	m.Axes[key.AxisLeftY] = 0
+	m.Invert[key.AxisLeftY] = true
+
+	f := Fake{Gamepads: GamepadFunc(func(j key.Joystick) Gamepad {
+		if j != key.Joystick3 {
+			return Gamepad{}
+		}
+		return m.Map([]bool{true, true, false, true}, []float64{.5})
+	})}
+	f.Update()
+
+	testCases := []struct {
+		desc string
+		res  bool
+	}{
+		{"connected", f.GamepadConnected(key.Joystick3) && !f.GamepadConnected(key.Joystick1)},
+		{"remapped", f.GamepadJustPressed(key.Joystick3, key.GamepadA)},
+		{"unmapped", !f.GamepadPressed(key.Joystick3, key.GamepadB)},
+		{"default", f.GamepadPressed(key.Joystick3, key.GamepadX) == false},
+		{"inverted", f.GamepadAxis(key.Joystick3, key.AxisLeftY) == -.5},
+		{"missing axis", f.GamepadAxis(key.Joystick3, key.AxisRightX) == 0},
+		{"invalid", !f.GamepadPressed(key.JoystickLast+1, key.GamepadA)},
+		{"deadzone", Deadzone(.1, .2) == 0 && Deadzone(-.75, .5) == -.5 && Deadzone(1, .2) == 1},
+	}
+
+	for _, tc := range testCases {
+		if !tc.res {
+			t.Error(tc.desc)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package input
+
+import (
+	"io/ioutil"
+
+	"github.com/jakubDoka/mlok/ggl/key"
+	"github.com/jakubDoka/mlok/logic/netw"
+	"github.com/jakubDoka/sterr"
+)
+
+// Recording related errors
+var (
+	ErrRecording = sterr.New("recording is corrupted or has incompatible version")
+)
+
+// recordVersion is written at the beginning of recording
+const recordVersion uint16 = 1
+
+// Recorder captures input frame by frame so it can be replayed later with Replay. It is
+// useful for reproducing bugs, record the session and replay it with Fake window-less
+// setup or send it with bug report.
+//
+//	rec := input.NRecorder(win)
+//	for !win.ShouldClose() {
+//		delta := ...
+//		rec.Record(delta)
+//		processor.Update(win, delta)
+//		win.Update()
+//	}
+//	rec.Save("session.rec")
+type Recorder struct {
+	Input
+	Buffer netw.Buffer
+}
+
+// NRecorder creates recorder of given input
+func NRecorder(inp Input) *Recorder {
+	r := &Recorder{Input: inp}
+	r.Buffer.PutUint16(recordVersion)
+	return r
+}
+
+// Record captures current state of input, delta is recorded too so replay does not
+// depend on frame rate
+func (r *Recorder) Record(delta float64) {
+	b := &r.Buffer
+	b.PutFloat64(delta)
+	b.PutVec(r.MousePos())
+	b.PutBool(r.MouseIsInside())
+	b.PutVec(r.MouseScroll())
+	b.PutString(r.Typed())
+	putKeys(b, r.Pressed)
+	putKeys(b, r.Repeated)
+
+	var connected []key.Joystick
+	for j := key.Joystick(0); j <= key.JoystickLast; j++ {
+		if r.GamepadConnected(j) {
+			connected = append(connected, j)
+		}
+	}
+	b.PutUint16(uint16(len(connected)))
+	for _, j := range connected {
+		b.PutUint16(uint16(j))
+		for bt := key.GamepadButton(0); bt <= key.GamepadLast; bt++ {
+			b.PutBool(r.GamepadPressed(j, bt))
+		}
+		for a := key.Axis(0); a <= key.AxisLast; a++ {
+			b.PutFloat64(r.GamepadAxis(j, a))
+		}
+	}
+}
+
+// Save writes recording to file
+func (r *Recorder) Save(path string) error {
+	return ioutil.WriteFile(path, r.Buffer.Data, 0644)
+}
+
+// Replay plays back recording, it implements Input. Call Next each frame before you pass
+// it to anything.
+//
+//	rep, err := input.LoadReplay("session.rec")
+//	...
+//	for {
+//		delta, ok := rep.Next()
+//		if !ok {
+//			break
+//		}
+//		processor.Update(rep, delta)
+//	}
+type Replay struct {
+	Frames
+	Buffer netw.Buffer
+}
+
+// NReplay creates replay from recorded data
+func NReplay(data []byte) (*Replay, error) {
+	r := &Replay{Buffer: netw.Buffer{Data: data}}
+	if r.Buffer.Uint16() != recordVersion || r.Buffer.Failed {
+		return nil, ErrRecording
+	}
+	return r, nil
+}
+
+// LoadReplay loads replay from file created by Recorder.Save
+func LoadReplay(path string) (*Replay, error) {
+	bts, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NReplay(bts)
+}
+
+// Next advances replay by one frame and returns recorded delta, ok is false if there are
+// no more frames, state is then left unchanged
+func (r *Replay) Next() (delta float64, ok bool) {
+	b := &r.Buffer
+	if b.Finished() || b.Failed {
+		return 0, false
+	}
+
+	var s State
+	delta = b.Float64()
+	s.Mouse = b.Vec()
+	s.Inside = b.Bool()
+	s.Scroll = b.Vec()
+	s.Typed = b.String()
+	readKeys(b, &s.Buttons)
+	readKeys(b, &s.Repeat)
+
+	l := int(b.Uint16())
+	for i := 0; i < l && !b.Failed; i++ {
+		var g Gamepad
+		g.Connected = true
+		j := key.Joystick(b.Uint16())
+		for bt := range g.Buttons {
+			g.Buttons[bt] = b.Bool()
+		}
+		for a := range g.Axes {
+			g.Axes[a] = b.Float64()
+		}
+		if validJoystick(j) {
+			s.Gamepads[j] = g
+		}
+	}
+
+	if b.Failed {
+		return 0, false
+	}
+
+	r.Push(s)
+	return delta, true
+}
+
+// putKeys writes all keys for witch pred returns true
+func putKeys(b *netw.Buffer, pred func(key.Key) bool) {
+	var keys []uint16
+	for k := key.Key(0); k <= key.Last; k++ {
+		if pred(k) {
+			keys = append(keys, uint16(k))
+		}
+	}
+	b.PutUint16(uint16(len(keys)))
+	for _, k := range keys {
+		b.PutUint16(k)
+	}
+}
+
+// readKeys reads keys written by putKeys
+func readKeys(b *netw.Buffer, keys *[key.Last + 1]bool) {
+	l := int(b.Uint16())
+	for i := 0; i < l && !b.Failed; i++ {
+		if k := key.Key(b.Uint16()); valid(k) {
+			keys[k] = true
+		}
+	}
+}
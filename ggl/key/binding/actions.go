@@ -0,0 +1,345 @@
+package binding
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"strings"
+
+	"github.com/jakubDoka/goml/goss"
+	"github.com/jakubDoka/mlok/ggl/input"
+	"github.com/jakubDoka/mlok/ggl/key"
+	"github.com/jakubDoka/mlok/load"
+	"github.com/jakubDoka/sterr"
+)
+
+// Map related errors
+var (
+	ErrLoadMap = sterr.New("failed to load bindings from '%s'")
+	ErrAction  = sterr.New("invalid bindings of action '%s'")
+)
+
+// goss style holding settings of the map
+const settings = "settings"
+
+// Map is rebindable mapping of actions, each action can have several alternative inputs
+// and it can also be an axis controlled by two groups of inputs. Actions are indexed by B
+// just like S and Map updates S so you can still send it over network with S.Write:
+//
+//	const (
+//		Save binding.B = iota
+//		MoveX
+//	)
+//
+//	var Actions = binding.NMap(
+//		binding.NAction("save", "Ctrl+S", "GamepadStart"),
+//		binding.NAxis("move_x", []string{"A", "Left", "AxisLeftX-"}, []string{"D", "Right", "AxisLeftX+"}),
+//	)
+//
+//	state := Actions.S()
+//	err := Actions.Load(load.OS, "bindings.json") // players rebindings
+//	...
+//	Actions.Update(state, win)
+//	state.JustPressed(Save)
+//	state.Value(MoveX) // -1..1
+//
+// Key is pressed even if modifiers are held unless it is part of pressed chord with more
+// modifiers from the same map, so Ctrl+S does not trigger action bound to S but Shift+W
+// still triggers action bound to W.
+type Map struct {
+	// Joystick overrides joystick of all gamepad targets so each player can have copy of
+	// the map with its own joystick
+	Joystick key.Joystick
+	Actions  []Action
+
+	chords []Target
+}
+
+// Action is named group of targets, name is used when map is saved and loaded
+type Action struct {
+	Name string
+	// Inputs are alternative targets of action, for axis they move it to positive side
+	Inputs []Target
+	// Negative makes action an axis, value of axis is sum of inputs minus sum of negative
+	// clamped to -1..1
+	Negative []Target
+}
+
+// NAction creates action from target strings, it panics on invalid target so it is meant
+// for default bindings defined in code
+func NAction(name string, inputs ...string) Action {
+	return Action{Name: name, Inputs: mustParseAll(inputs)}
+}
+
+// NAxis creates axis action from target strings, panics as NAction
+func NAxis(name string, negative, positive []string) Action {
+	return Action{Name: name, Inputs: mustParseAll(positive), Negative: mustParseAll(negative)}
+}
+
+// IsAxis returns whether action is axis
+func (a *Action) IsAxis() bool {
+	return len(a.Negative) != 0
+}
+
+// NMap creates map from actions
+func NMap(actions ...Action) *Map {
+	return &Map{Actions: actions}
+}
+
+// S creates binding state for map
+func (m *Map) S() S {
+	return make(S, len(m.Actions))
+}
+
+// Index returns index of action with given name
+func (m *Map) Index(name string) (B, bool) {
+	for i := range m.Actions {
+		if m.Actions[i].Name == name {
+			return B(i), true
+		}
+	}
+	return 0, false
+}
+
+// Bind replaces inputs of action, negative inputs are kept
+func (m *Map) Bind(bid B, inputs ...Target) {
+	m.Actions[bid].Inputs = inputs
+}
+
+// Update updates s according to map and returns whether change happened
+//
+// panics if s is shorter then amount of actions
+func (m *Map) Update(s S, w input.Input) (changed bool) {
+	m.chords = m.chords[:0]
+	for i := range m.Actions {
+		a := &m.Actions[i]
+		for _, targets := range [...][]Target{a.Inputs, a.Negative} {
+			for _, t := range targets {
+				if t.Kind == KeyKind && t.Mods != 0 && w.Pressed(t.Key) && t.Mods.Held(w) {
+					m.chords = append(m.chords, t)
+				}
+			}
+		}
+	}
+
+	for i := range m.Actions {
+		pressed, value := m.Actions[i].read(w, m.Joystick, m.chords)
+		if s[i].set(pressed, value) {
+			changed = true
+		}
+	}
+	return
+}
+
+// read returns whether action is pressed and its value, key targets shadowed by chords
+// are ignored
+func (a *Action) read(w input.Input, j key.Joystick, chords []Target) (pressed bool, value float64) {
+	if !a.IsAxis() {
+		for _, t := range a.Inputs {
+			if p, v := t.amount(w, j, chords); p {
+				pressed, value = true, math.Max(value, v)
+			}
+		}
+		return
+	}
+
+	for _, t := range a.Inputs {
+		p, v := t.amount(w, j, chords)
+		pressed = pressed || p
+		value += v
+	}
+	for _, t := range a.Negative {
+		p, v := t.amount(w, j, chords)
+		pressed = pressed || p
+		value -= v
+	}
+	return pressed, math.Max(-1, math.Min(1, value))
+}
+
+// amount is like read but value of axis side is its distance in direction of the side
+func (t Target) amount(w input.Input, j key.Joystick, chords []Target) (bool, float64) {
+	if t.shadowed(chords) {
+		return false, 0
+	}
+	t.Joystick = j
+	p, v := t.read(w)
+	switch {
+	case t.Kind != AxisKind || t.Threshold == 0:
+		return p, v
+	case t.Threshold < 0:
+		return p, math.Max(-v, 0)
+	default:
+		return p, math.Max(v, 0)
+	}
+}
+
+// shadowed returns whether key target is part of some chord with more modifiers
+func (t Target) shadowed(chords []Target) bool {
+	if t.Kind != KeyKind {
+		return false
+	}
+	for _, c := range chords {
+		if c.Key == t.Key && c.Mods != t.Mods && c.Mods&t.Mods == t.Mods {
+			return true
+		}
+	}
+	return false
+}
+
+// mapFile is persisted form of Map
+type mapFile struct {
+	Joystick key.Joystick          `json:"joystick"`
+	Actions  map[string]actionFile `json:"actions"`
+}
+
+type actionFile struct {
+	Inputs   []Target `json:"inputs"`
+	Negative []Target `json:"negative,omitempty"`
+}
+
+// Save saves the map, format is chosen by extension, it is goss for ".goss" and json
+// otherwise
+func (m *Map) Save(u load.Util, p string) error {
+	if path.Ext(p) == ".goss" {
+		return u.Save(p, m.Goss())
+	}
+
+	f := mapFile{Joystick: m.Joystick, Actions: map[string]actionFile{}}
+	for _, a := range m.Actions {
+		f.Actions[a.Name] = actionFile{a.Inputs, a.Negative}
+	}
+	return u.SaveJson(p, f)
+}
+
+// Load loads bindings saved by Save, actions are matched by name, actions that are not
+// in the file keep their bindings and unknown actions are ignored so old files still work
+// after you add or remove actions. Format is chosen by extension same as in Save.
+//
+// goss format looks like:
+//
+//	settings{
+//		joystick: 0;
+//	}
+//	save{
+//		inputs: Ctrl_S GamepadStart;
+//	}
+//	move_x{
+//		inputs: D Right AxisLeftX_pos;
+//		negative: A Left AxisLeftX_neg;
+//	}
+//
+// goss values have to be identifiers so '+' separating modifiers is written as '_' and
+// sign of axis as _pos or _neg suffix.
+func (m *Map) Load(u load.Util, p string) error {
+	var f mapFile
+	if path.Ext(p) == ".goss" {
+		styles, err := u.Goss(p)
+		if err != nil {
+			return ErrLoadMap.Args(p).Wrap(err)
+		}
+		f, err = fromGoss(styles)
+		if err != nil {
+			return ErrLoadMap.Args(p).Wrap(err)
+		}
+	} else if err := u.Json(p, &f); err != nil {
+		return ErrLoadMap.Args(p).Wrap(err)
+	}
+
+	m.Joystick = f.Joystick
+	for i := range m.Actions {
+		a := &m.Actions[i]
+		if af, ok := f.Actions[a.Name]; ok {
+			a.Inputs, a.Negative = af.Inputs, af.Negative
+		}
+	}
+	return nil
+}
+
+// Goss returns goss representation of map
+func (m *Map) Goss() []byte {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s{\n\tjoystick: %d;\n}\n", settings, m.Joystick)
+	for _, a := range m.Actions {
+		fmt.Fprintf(&sb, "%s{\n", a.Name)
+		writeTargets(&sb, "inputs", a.Inputs)
+		if a.IsAxis() {
+			writeTargets(&sb, "negative", a.Negative)
+		}
+		sb.WriteString("}\n")
+	}
+	return []byte(sb.String())
+}
+
+func writeTargets(sb *strings.Builder, name string, targets []Target) {
+	sb.WriteString("\t" + name + ":")
+	for _, t := range targets {
+		sb.WriteString(" " + gossName(t))
+	}
+	sb.WriteString(";\n")
+}
+
+// gossName encodes target as goss identifier
+func gossName(t Target) string {
+	s := t.String()
+	switch s[len(s)-1] {
+	case '+':
+		s = s[:len(s)-1] + "_pos"
+	case '-':
+		s = s[:len(s)-1] + "_neg"
+	}
+	return strings.ReplaceAll(s, "+", "_")
+}
+
+// parseGossName is inverse of gossName
+func parseGossName(s string) (Target, error) {
+	switch {
+	case strings.HasSuffix(s, "_pos"):
+		s = s[:len(s)-4] + "+"
+	case strings.HasSuffix(s, "_neg"):
+		s = s[:len(s)-4] + "-"
+	}
+	return Parse(strings.ReplaceAll(s, "_", "+"))
+}
+
+// fromGoss converts parsed goss to mapFile
+func fromGoss(styles goss.Styles) (f mapFile, err error) {
+	f.Actions = map[string]actionFile{}
+	for name, st := range styles {
+		if name == settings {
+			f.Joystick = key.Joystick(load.RawStyle{Style: st}.Int("joystick", 0))
+			continue
+		}
+
+		var af actionFile
+		if af.Inputs, err = parseAll(st["inputs"]); err != nil {
+			return f, ErrAction.Args(name).Wrap(err)
+		}
+		if af.Negative, err = parseAll(st["negative"]); err != nil {
+			return f, ErrAction.Args(name).Wrap(err)
+		}
+		f.Actions[name] = af
+	}
+	return
+}
+
+// parseAll parses goss values as targets encoded by gossName, values can be numbers as goss
+// parses key names like 1 as integers
+func parseAll(values []interface{}) ([]Target, error) {
+	targets := make([]Target, 0, len(values))
+	for _, v := range values {
+		t, err := parseGossName(fmt.Sprint(v))
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, t)
+	}
+	return targets, nil
+}
+
+func mustParseAll(values []string) []Target {
+	targets := make([]Target, len(values))
+	for i, v := range values {
+		targets[i] = MustParse(v)
+	}
+	return targets
+}
@@ -4,8 +4,9 @@ package binding
 
 import (
 	"fmt"
+	"math"
 
-	"github.com/jakubDoka/mlok/ggl"
+	"github.com/jakubDoka/mlok/ggl/input"
 	"github.com/jakubDoka/mlok/ggl/key"
 	"github.com/jakubDoka/mlok/logic/netw"
 )
@@ -26,6 +27,15 @@ import (
 // player for example. You can now create bindings with any key combination and listen to them with same
 // constants. Other advantage is that you can Write and read bindings to netw.Buffer so stransporting input
 // is lot easier.
+//
+// Bindings can also target gamepad buttons and axes, see NTargets:
+//
+//	var Bindings = binding.NTargets(
+//		binding.Axis(key.Joystick1, key.AxisLeftY, .2, -.5), // forward, stick up is negative
+//		binding.Axis(key.Joystick1, key.AxisLeftX, .2, .5),
+//		binding.Axis(key.Joystick1, key.AxisLeftX, .2, -.5),
+//		binding.Button(key.Joystick1, key.GamepadB),
+//	)
 type S []state
 
 // New creates binding mapping to witch you can index with your constant
@@ -38,24 +48,44 @@ func New(keys ...key.Key) S {
 	return s
 }
 
-// Write writes input to the buffer
+// NTargets creates binding mapping from targets, targets can be keys, gamepad buttons or axes
+func NTargets(targets ...Target) S {
+	s := make(S, len(targets))
+	for i := range s {
+		s[i].Target = targets[i]
+	}
+
+	return s
+}
+
+// valueFormat is set in length header of S written with values, header of older format
+// without values does not have it so peers using different formats panic in Read instead
+// of misreading the states
+const valueFormat = 1 << 15
+
+// Write writes input to the buffer, Value is written with precision of 1/127
 func (s S) Write(b *netw.Buffer) {
-	b.PutUint16(uint16(len(s)))
+	b.PutUint16(uint16(len(s)) | valueFormat)
 	for _, bid := range s {
-		b.Data = append(b.Data, byte(bid.State))
+		b.Data = append(b.Data, byte(bid.State), byte(int8(math.Round(math.Max(-1, math.Min(1, bid.Value))*127))))
 	}
 }
 
 // Read reads S state from buffer
 //
-// panics if length does not match
+// panics if length or format does not match
 func (s S) Read(b *netw.Buffer) {
 	l := b.Uint16()
+	if l&valueFormat == 0 {
+		panic(fmt.Errorf("input state was written in format without values, both sides have to use the same version"))
+	}
+	l &^= valueFormat
 	if l != uint16(len(s)) {
 		panic(fmt.Errorf("length of input state (len=%d) written in buffer does not match readers length(len=%d)", l, len(s)))
 	}
 	for i := range s {
 		s[i].State = State(b.Byte())
+		s[i].Value = float64(int8(b.Byte())) / 127
 	}
 }
 
@@ -67,22 +97,10 @@ func (s S) Clone() S {
 }
 
 // Update updates S states and returns whether change happened
-func (s S) Update(w *ggl.Window) (changed bool) {
+func (s S) Update(w input.Input) (changed bool) {
 	for i := range s {
 		b := &s[i]
-		old := b.State
-
-		if w.JustPressed(b.Key) {
-			b.State = JustPressed
-		} else if w.Pressed(b.Key) {
-			b.State = Pressed
-		} else if w.JustReleased(b.Key) {
-			b.State = JustReleased
-		} else {
-			b.State = Released
-		}
-
-		if b.State != old {
+		if b.set(b.Target.read(w)) {
 			changed = true
 		}
 	}
@@ -100,6 +118,15 @@ func (s S) State(bid B) State {
 	return s[bid].State
 }
 
+// Value returns analog value of binding, for axis it is value after deadzone is applied,
+// for keys and buttons it is 1 if pressed and 0 otherwise
+//
+// panics at sam cases as State
+func (s S) Value(bid B) float64 {
+	s.State(bid)
+	return s[bid].Value
+}
+
 // Pressed returns whether binding is pressed
 //
 // panics at sam cases as State
@@ -122,8 +149,95 @@ func (s S) JustReleased(bid B) bool {
 }
 
 type state struct {
-	key.Key
+	Target
 	State
+	Value float64
+}
+
+// set updates the state and returns whether it changed
+func (s *state) set(pressed bool, value float64) bool {
+	old := s.State
+	s.Value = value
+	switch {
+	case pressed && !old.Down():
+		s.State = JustPressed
+	case pressed:
+		s.State = Pressed
+	case old.Down():
+		s.State = JustReleased
+	default:
+		s.State = Released
+	}
+	return s.State != old
+}
+
+// Kind is kind of input Target reacts to
+type Kind uint8
+
+// Kind enum
+const (
+	KeyKind Kind = iota
+	ButtonKind
+	AxisKind
+)
+
+// Target is input that binding reacts to, use Key, Button and Axis to create it or
+// parse it from string with Parse
+type Target struct {
+	Kind Kind
+	Key  key.Key
+	// Mods have to be held for key target to be pressed
+	Mods     Mod
+	Joystick key.Joystick
+	Button   key.GamepadButton
+	Axis     key.Axis
+	// Deadzone is part of axis range around zero that is ignored
+	Deadzone float64
+	// Threshold is value of axis (after deadzone) from witch binding counts as pressed,
+	// negative threshold makes binding react to negative direction of axis
+	Threshold float64
+}
+
+// Key creates keyboard or mouse target
+func Key(k key.Key) Target {
+	return Target{Kind: KeyKind, Key: k}
+}
+
+// Button creates gamepad button target
+func Button(j key.Joystick, b key.GamepadButton) Target {
+	return Target{Kind: ButtonKind, Joystick: j, Button: b}
+}
+
+// Axis creates gamepad axis target, binding is pressed when axis goes over threshold
+// in direction of threshold sign, zero threshold means any movement outside deadzone
+func Axis(j key.Joystick, a key.Axis, deadzone, threshold float64) Target {
+	return Target{Kind: AxisKind, Joystick: j, Axis: a, Deadzone: deadzone, Threshold: threshold}
+}
+
+// read returns whether target is pressed and its analog value
+func (t *Target) read(w input.Input) (bool, float64) {
+	var pressed bool
+	switch t.Kind {
+	case KeyKind:
+		pressed = w.Pressed(t.Key) && t.Mods.Held(w)
+	case ButtonKind:
+		pressed = w.GamepadPressed(t.Joystick, t.Button)
+	case AxisKind:
+		v := input.Deadzone(w.GamepadAxis(t.Joystick, t.Axis), t.Deadzone)
+		switch {
+		case t.Threshold < 0:
+			return v < 0 && v <= t.Threshold, v
+		case t.Threshold > 0:
+			return v > 0 && v >= t.Threshold, v
+		default:
+			return v != 0, v
+		}
+	}
+
+	if pressed {
+		return true, 1
+	}
+	return false, 0
 }
 
 // B is a arbitrary binding, should be declared with iota
@@ -139,3 +253,8 @@ const (
 	Pressed
 	JustPressed
 )
+
+// Down returns whether state is Pressed or JustPressed
+func (s State) Down() bool {
+	return s >= Pressed
+}
@@ -0,0 +1,303 @@
+package binding
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jakubDoka/mlok/ggl/input"
+	"github.com/jakubDoka/mlok/ggl/key"
+	"github.com/jakubDoka/mlok/load"
+	"github.com/jakubDoka/mlok/logic/netw"
+)
+
+func TestUpdate(t *testing.T) {
+	const (
+		Jump B = iota
+		Fire
+		Left
+		Right
+	)
+
+	s := NTargets(
+		Key(key.Space),
+		Button(key.Joystick2, key.GamepadA),
+		Axis(key.Joystick2, key.AxisLeftX, .2, -.5),
+		Axis(key.Joystick2, key.AxisLeftX, .2, .5),
+	)
+
+	var f input.Fake
+	testCases := []struct {
+		desc   string
+		do     func()
+		states [4]State
+		value  float64
+	}{
+		{
+			desc: "press",
+			do: func() {
+				f.Press(key.Space)
+				f.PressGamepad(key.Joystick2, key.GamepadA)
+			},
+			states: [4]State{JustPressed, JustPressed, Released, Released},
+		},
+		{
+			desc:   "deadzone",
+			do:     func() { f.MoveAxis(key.Joystick2, key.AxisLeftX, .1) },
+			states: [4]State{Pressed, Pressed, Released, Released},
+		},
+		{
+			desc:   "under threshold",
+			do:     func() { f.MoveAxis(key.Joystick2, key.AxisLeftX, .6) },
+			states: [4]State{Pressed, Pressed, Released, Released},
+			value:  .5,
+		},
+		{
+			desc: "over threshold",
+			do: func() {
+				f.Release(key.Space)
+				f.MoveAxis(key.Joystick2, key.AxisLeftX, 1)
+			},
+			states: [4]State{JustReleased, Pressed, Released, JustPressed},
+			value:  1,
+		},
+		{
+			desc: "negative",
+			do: func() {
+				f.ReleaseGamepad(key.Joystick2, key.GamepadA)
+				f.MoveAxis(key.Joystick2, key.AxisLeftX, -.8)
+			},
+			states: [4]State{Released, JustReleased, JustPressed, JustReleased},
+			value:  -.75,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			tc.do()
+			f.Update()
+			s.Update(&f)
+			for i, st := range tc.states {
+				if s.State(B(i)) != st {
+					t.Errorf("%d\n%v\n%v", i, s.State(B(i)), st)
+				}
+			}
+			if v := s.Value(Right); v < tc.value-1e-9 || v > tc.value+1e-9 {
+				t.Errorf("\n%v\n%v", v, tc.value)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		desc, in, out string
+		err           bool
+	}{
+		{desc: "key", in: "a", out: "A"},
+		{desc: "chord", in: "shift + ctrl+S", out: "Ctrl+Shift+S"},
+		{desc: "digit", in: "Ctrl+1", out: "Ctrl+1"},
+		{desc: "button", in: "gamepadstart", out: "GamepadStart"},
+		{desc: "axis", in: "AxisLeftX", out: "AxisLeftX"},
+		{desc: "axis side", in: "AxisRightTrigger+", out: "AxisRightTrigger+"},
+		{desc: "minus", in: "Minus", out: "Minus"},
+		{desc: "not modifier", in: "A+S", err: true},
+		{desc: "unknown", in: "Ctrl+Foo", err: true},
+		{desc: "empty", in: " ", err: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			res, err := Parse(tc.in)
+			if (err != nil) != tc.err {
+				t.Fatal(err)
+			}
+			if !tc.err && res.String() != tc.out {
+				t.Errorf("\n%v\n%v", res, tc.out)
+			}
+		})
+	}
+}
+
+func TestMap(t *testing.T) {
+	const (
+		Save B = iota
+		Jump
+		MoveX
+	)
+
+	m := NMap(
+		NAction("save", "Ctrl+S"),
+		NAction("jump", "Space", "W", "GamepadA"),
+		NAxis("move_x", []string{"A", "AxisLeftX-"}, []string{"D", "AxisLeftX+"}),
+	)
+	m.Joystick = key.Joystick2
+	s := m.S()
+
+	var f input.Fake
+	testCases := []struct {
+		desc   string
+		do     func()
+		states [3]State
+		value  float64
+	}{
+		{
+			desc:   "no modifier",
+			do:     func() { f.Press(key.S, key.D) },
+			states: [3]State{Released, Released, JustPressed},
+			value:  1,
+		},
+		{
+			desc:   "chord",
+			do:     func() { f.Press(key.RightControl, key.A) },
+			states: [3]State{JustPressed, Released, Pressed},
+		},
+		{
+			desc: "alternative",
+			do: func() {
+				f.Release(key.RightControl, key.S, key.D)
+				f.PressGamepad(key.Joystick2, key.GamepadA)
+			},
+			states: [3]State{JustReleased, JustPressed, Pressed},
+			value:  -1,
+		},
+		{
+			desc: "analog",
+			do: func() {
+				f.Release(key.A)
+				f.MoveAxis(key.Joystick2, key.AxisLeftX, .8)
+			},
+			states: [3]State{Released, Pressed, Pressed},
+			value:  .75,
+		},
+		{
+			desc:   "other joystick",
+			do:     func() { f.ReleaseGamepad(key.Joystick2, key.GamepadA); f.PressGamepad(key.Joystick1, key.GamepadA) },
+			states: [3]State{Released, JustReleased, Pressed},
+			value:  .75,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			tc.do()
+			f.Update()
+			m.Update(s, &f)
+			for i, st := range tc.states {
+				if s.State(B(i)) != st {
+					t.Errorf("%d\n%v\n%v", i, s.State(B(i)), st)
+				}
+			}
+			if v := s.Value(MoveX); math.Abs(v-tc.value) > 1e-9 {
+				t.Errorf("\n%v\n%v", v, tc.value)
+			}
+		})
+	}
+
+	var b netw.Buffer
+	s.Write(&b)
+	r := m.S()
+	r.Read(&b)
+	if r.State(MoveX) != s.State(MoveX) || math.Abs(r.Value(MoveX)-.75) > 1./127 {
+		t.Errorf("\n%v\n%v", r.Value(MoveX), s.Value(MoveX))
+	}
+}
+
+func TestMapChord(t *testing.T) {
+	const (
+		Save B = iota
+		MoveY
+	)
+
+	m := NMap(
+		NAction("save", "Ctrl+S"),
+		NAxis("move_y", []string{"S"}, []string{"W"}),
+	)
+	s := m.S()
+
+	var f input.Fake
+	testCases := []struct {
+		desc   string
+		do     func()
+		states [2]State
+		value  float64
+	}{
+		{"key", func() { f.Press(key.S) }, [2]State{Released, JustPressed}, -1},
+		{"chord", func() { f.Press(key.LeftControl) }, [2]State{JustPressed, JustReleased}, 0},
+		{"other key", func() { f.Press(key.W) }, [2]State{Pressed, JustPressed}, 1},
+		{"chord released", func() { f.Release(key.LeftControl, key.W) }, [2]State{JustReleased, Pressed}, -1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			tc.do()
+			f.Update()
+			m.Update(s, &f)
+			for i, st := range tc.states {
+				if s.State(B(i)) != st {
+					t.Errorf("%d\n%v\n%v", i, s.State(B(i)), st)
+				}
+			}
+			if v := s.Value(MoveY); v != tc.value {
+				t.Errorf("\n%v\n%v", v, tc.value)
+			}
+		})
+	}
+}
+
+func TestNetw(t *testing.T) {
+	s := NTargets(Key(key.A), Axis(key.Joystick1, key.AxisLeftX, 0, 0))
+	s[0].State, s[0].Value = JustPressed, 1
+	s[1].State, s[1].Value = Pressed, -.5
+
+	var b netw.Buffer
+	s.Write(&b)
+	r := s.Clone()
+	r[0], r[1] = state{}, state{}
+	r.Read(&b)
+	for i := range s {
+		if r[i].State != s[i].State || math.Abs(r[i].Value-s[i].Value) > 1./127 {
+			t.Errorf("%d\n%v %v\n%v %v", i, r[i].State, r[i].Value, s[i].State, s[i].Value)
+		}
+	}
+
+	// old format has just the length and state bytes
+	b = netw.Buffer{}
+	b.PutUint16(2)
+	b.Data = append(b.Data, byte(Pressed), byte(Released))
+	defer func() {
+		if recover() == nil {
+			t.Error("old format was read")
+		}
+	}()
+	r.Read(&b)
+}
+
+func TestMapPersist(t *testing.T) {
+	u := load.Util{Loader: load.OSFS{}, Root: t.TempDir()}
+	for _, ext := range []string{".json", ".goss"} {
+		t.Run(ext, func(t *testing.T) {
+			m := NMap(
+				NAction("save", "Ctrl+S"),
+				NAxis("move_x", []string{"A"}, []string{"D", "AxisLeftX-"}),
+			)
+			m.Joystick = key.Joystick3
+			m.Bind(0, MustParse("Ctrl+Shift+1"), MustParse("GamepadStart"))
+			if err := m.Save(u, "bindings"+ext); err != nil {
+				t.Fatal(err)
+			}
+
+			d := NMap(
+				NAction("save", "Ctrl+S"),
+				NAxis("move_x", []string{"Left"}, []string{"Right"}),
+				NAction("new", "N"),
+			)
+			if err := d.Load(u, "bindings"+ext); err != nil {
+				t.Fatal(err)
+			}
+
+			if d.Joystick != key.Joystick3 || string(d.Goss()) != string(m.Goss())+"new{\n\tinputs: N;\n}\n" {
+				t.Errorf("\n%s\n%s", d.Goss(), m.Goss())
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package binding
+
+import "math"
+
+// Combo is sequence of steps that has to be performed in time window, each step is set of
+// bindings that have to be held together, the step is completed by pressing the last of
+// them. For example quarter circle forward punch can look like:
+//
+//	binding.Combo{
+//		Name:     "fireball",
+//		Steps:    [][]binding.B{{Down}, {Down, Forward}, {Forward, Punch}},
+//		Window:   .3,
+//		Leniency: .05,
+//		Priority: 1,
+//	}
+type Combo struct {
+	Name  string
+	Steps [][]B
+	// Window is maximal time between first and last step
+	Window float64
+	// Leniency is how long binding still counts as held after it was released, so
+	// bindings of one step does not have to overlap precisely
+	Leniency float64
+	// Priority decides witch combo wins when more of them complete at the same time, when
+	// priorities are equal, combo with more steps wins
+	Priority int
+}
+
+// Event is change of binding state recorded by ComboBuffer
+type Event struct {
+	Binding B
+	Pressed bool
+	Time    float64
+}
+
+// ComboBuffer keeps timestamped history of binding state changes and detects combos.
+// Feed it with S each frame, S can be updated from window, Map or filled manually:
+//
+//	combos := binding.NComboBuffer(fireball, punch)
+//	...
+//	bindings.Update(win)
+//	if c, ok := combos.Update(bindings, delta); ok {
+//		switch combos.Combos[c].Name {
+//		...
+//	}
+//
+// Events used by matched combo are consumed so they cannot be part of other combo, for
+// example fireball consumes the punch so punch combo does not trigger together with it.
+type ComboBuffer struct {
+	Combos []Combo
+
+	time     float64
+	events   []Event
+	consumed int
+	down     []bool
+	base     map[B]bool
+}
+
+// NComboBuffer creates buffer detecting given combos
+func NComboBuffer(combos ...Combo) *ComboBuffer {
+	return &ComboBuffer{Combos: combos}
+}
+
+// Update records changes of s and returns index of combo that completed in this frame,
+// ok is false if none did
+func (c *ComboBuffer) Update(s S, delta float64) (combo int, ok bool) {
+	c.time += delta
+	c.trim()
+
+	pressed := false
+	for i := range s {
+		for len(c.down) <= i {
+			c.down = append(c.down, false)
+		}
+		down := s[i].State.Down()
+		if down != c.down[i] {
+			c.down[i] = down
+			c.Record(B(i), down)
+			pressed = pressed || down
+		}
+	}
+
+	if !pressed {
+		return -1, false
+	}
+
+	return c.Match()
+}
+
+// Record adds event to history at current time, Update calls it for you but you can use
+// it to feed events directly
+func (c *ComboBuffer) Record(bid B, pressed bool) {
+	c.events = append(c.events, Event{bid, pressed, c.time})
+}
+
+// Match returns index of the combo with highest priority completed by press recorded in
+// current frame, events of matched combo are consumed
+func (c *ComboBuffer) Match() (combo int, ok bool) {
+	combo = -1
+	for i := range c.Combos {
+		cm := &c.Combos[i]
+		if !c.matches(cm) {
+			continue
+		}
+		if !ok || cm.Priority > c.Combos[combo].Priority ||
+			cm.Priority == c.Combos[combo].Priority && len(cm.Steps) > len(c.Combos[combo].Steps) {
+			combo, ok = i, true
+		}
+	}
+
+	if ok {
+		c.consumed = len(c.events)
+	}
+
+	return
+}
+
+// Events returns recorded history, it can be useful for debugging or input display
+func (c *ComboBuffer) Events() []Event {
+	return c.events
+}
+
+// Time returns time buffer counted from creation
+func (c *ComboBuffer) Time() float64 {
+	return c.time
+}
+
+// Clear forgets the history
+func (c *ComboBuffer) Clear() {
+	c.trimTo(len(c.events))
+	c.consumed = 0
+}
+
+// matches returns whether combo completed in this frame, steps are searched from the last
+// one and each takes the latest event that completes it
+func (c *ComboBuffer) matches(cm *Combo) bool {
+	if len(cm.Steps) == 0 {
+		return false
+	}
+
+	end := len(c.events)
+	for i := len(cm.Steps) - 1; i >= 0; i-- {
+		step := cm.Steps[i]
+		found := false
+		for end > c.consumed {
+			end--
+			e := c.events[end]
+			if c.time-e.Time > cm.Window || i == len(cm.Steps)-1 && e.Time != c.time {
+				return false
+			}
+			if e.Pressed && contains(step, e.Binding) && c.held(step, e.Time, cm.Leniency) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// held returns whether all bindings were held at time t with given leniency
+func (c *ComboBuffer) held(step []B, t, leniency float64) bool {
+o:
+	for _, bid := range step {
+		for i := len(c.events) - 1; i >= 0; i-- {
+			e := c.events[i]
+			if e.Binding != bid || e.Time > t {
+				continue
+			}
+			if e.Pressed || t-e.Time <= leniency {
+				continue o
+			}
+			return false
+		}
+		if !c.base[bid] {
+			return false
+		}
+	}
+	return true
+}
+
+// trim removes events that are too old to be part of any combo
+func (c *ComboBuffer) trim() {
+	length := 0.0
+	for _, cm := range c.Combos {
+		length = math.Max(length, cm.Window+cm.Leniency)
+	}
+
+	i := 0
+	for i < len(c.events) && c.time-c.events[i].Time > length {
+		i++
+	}
+	c.trimTo(i)
+}
+
+// trimTo removes first n events, state of removed events is kept in base
+func (c *ComboBuffer) trimTo(n int) {
+	if n == 0 {
+		return
+	}
+	if c.base == nil {
+		c.base = map[B]bool{}
+	}
+	for _, e := range c.events[:n] {
+		c.base[e.Binding] = e.Pressed
+	}
+	c.events = append(c.events[:0], c.events[n:]...)
+	c.consumed = int(math.Max(float64(c.consumed-n), 0))
+}
+
+func contains(bs []B, b B) bool {
+	for _, v := range bs {
+		if v == b {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,123 @@
+package binding
+
+import "testing"
+
+func TestComboBuffer(t *testing.T) {
+	const (
+		Down B = iota
+		Forward
+		Punch
+		Kick
+	)
+
+	fireball := Combo{
+		Name:     "fireball",
+		Steps:    [][]B{{Down}, {Down, Forward}, {Forward, Punch}},
+		Window:   .3,
+		Leniency: .05,
+		Priority: 1,
+	}
+	punch := Combo{Name: "punch", Steps: [][]B{{Punch}}}
+	kicks := Combo{Name: "kicks", Steps: [][]B{{Kick}, {Kick}}, Window: .2}
+
+	type frame struct {
+		delta float64
+		held  []B
+	}
+
+	testCases := []struct {
+		desc   string
+		frames []frame
+		res    string
+	}{
+		{
+			desc: "fireball",
+			frames: []frame{
+				{.05, []B{Down}},
+				{.05, []B{Down, Forward}},
+				{.05, []B{Forward}},
+				{.05, []B{Forward, Punch}},
+			},
+			res: "fireball",
+		},
+		{
+			desc: "leniency",
+			frames: []frame{
+				{.05, []B{Down}},
+				{.05, []B{Down, Forward}},
+				{.05, []B{}},
+				{.04, []B{Punch}},
+			},
+			res: "fireball",
+		},
+		{
+			desc: "no leniency",
+			frames: []frame{
+				{.05, []B{Down}},
+				{.05, []B{Down, Forward}},
+				{.05, []B{}},
+				{.1, []B{Punch}},
+			},
+			res: "punch",
+		},
+		{
+			desc: "too slow",
+			frames: []frame{
+				{.05, []B{Down}},
+				{.2, []B{Down, Forward}},
+				{.2, []B{Forward, Punch}},
+			},
+			res: "punch",
+		},
+		{
+			desc: "wrong order",
+			frames: []frame{
+				{.05, []B{Forward}},
+				{.05, []B{Down, Forward}},
+				{.05, []B{Down}},
+				{.05, []B{Down, Punch}},
+			},
+			res: "punch",
+		},
+		{
+			desc: "double kick",
+			frames: []frame{
+				{.05, []B{Kick}},
+				{.05, []B{}},
+				{.05, []B{Kick}},
+			},
+			res: "kicks",
+		},
+		{
+			desc: "consumed",
+			frames: []frame{
+				{.05, []B{Kick}},
+				{.05, []B{}},
+				{.05, []B{Kick}},
+				{.05, []B{}},
+				{.05, []B{Kick}},
+			},
+			res: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			s := make(S, 4)
+			c := NComboBuffer(punch, fireball, kicks)
+			var res string
+			for _, f := range tc.frames {
+				for i := range s {
+					s[i].set(contains(f.held, B(i)), 0)
+				}
+				res = ""
+				if i, ok := c.Update(s, f.delta); ok {
+					res = c.Combos[i].Name
+				}
+			}
+			if res != tc.res {
+				t.Errorf("\n%v\n%v\n%v", res, tc.res, c.Events())
+			}
+		})
+	}
+}
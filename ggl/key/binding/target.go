@@ -0,0 +1,158 @@
+package binding
+
+import (
+	"strings"
+
+	"github.com/jakubDoka/mlok/ggl/input"
+	"github.com/jakubDoka/mlok/ggl/key"
+	"github.com/jakubDoka/sterr"
+)
+
+// Target parsing errors
+var (
+	ErrInvalidTarget = sterr.New("invalid input '%s'")
+	ErrNotModifier   = sterr.New("'%s' is not a modifier, use Ctrl, Shift, Alt or Super")
+)
+
+// defaults used when target is parsed from string
+var (
+	DefaultDeadzone  = .2
+	DefaultThreshold = .5
+)
+
+// Mod is set of modifiers for key chords, left and right variant of modifier key are
+// equivalent
+type Mod uint8
+
+// Mod enum
+const (
+	Ctrl Mod = 1 << iota
+	Shift
+	Alt
+	Super
+)
+
+var modNames = [...]string{"Ctrl", "Shift", "Alt", "Super"}
+
+var modKeys = [...][2]key.Key{
+	{key.LeftControl, key.RightControl},
+	{key.LeftShift, key.RightShift},
+	{key.LeftAlt, key.RightAlt},
+	{key.LeftSuper, key.RightSuper},
+}
+
+// Held returns whether all modifiers are held
+func (m Mod) Held(w input.Input) bool {
+	for i, keys := range modKeys {
+		if m&(1<<i) != 0 && !w.Pressed(keys[0]) && !w.Pressed(keys[1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Parse parses target from string, syntax is:
+//
+//	Ctrl+Shift+S	// key with modifiers, key names are the same as key.Key.String returns
+//	GamepadA	// gamepad button, joystick is Joystick1
+//	AxisLeftX+	// positive side of gamepad axis, DefaultThreshold and DefaultDeadzone are used
+//	AxisLeftX-	// negative side
+//	AxisLeftX	// whole axis, pressed when outside deadzone
+//
+// names are case insensitive
+func Parse(s string) (t Target, err error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return t, ErrInvalidTarget.Args(s)
+	}
+
+	if b, err := key.ParseGamepad(s); err == nil {
+		return Button(key.Joystick1, b), nil
+	}
+
+	name, threshold := s, 0.0
+	switch s[len(s)-1] {
+	case '+':
+		name, threshold = s[:len(s)-1], DefaultThreshold
+	case '-':
+		name, threshold = s[:len(s)-1], -DefaultThreshold
+	}
+	if a, err := key.ParseAxis(name); err == nil {
+		return Axis(key.Joystick1, a, DefaultDeadzone, threshold), nil
+	}
+
+	parts := strings.Split(s, "+")
+	last := len(parts) - 1
+	for _, p := range parts[:last] {
+		m := modByName(p)
+		if m == 0 {
+			return t, ErrNotModifier.Args(p)
+		}
+		t.Mods |= m
+	}
+
+	t.Key, err = key.Parse(strings.TrimSpace(parts[last]))
+	if err != nil {
+		return t, ErrInvalidTarget.Args(s).Wrap(err)
+	}
+
+	return
+}
+
+// MustParse is like Parse but panics on error, it is meant for default bindings defined
+// in code
+func MustParse(s string) Target {
+	t, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// String is inverse of Parse, threshold and deadzone of axis are lost and only sign of
+// threshold is kept
+func (t Target) String() string {
+	switch t.Kind {
+	case ButtonKind:
+		return t.Button.String()
+	case AxisKind:
+		switch {
+		case t.Threshold > 0:
+			return t.Axis.String() + "+"
+		case t.Threshold < 0:
+			return t.Axis.String() + "-"
+		}
+		return t.Axis.String()
+	}
+
+	var sb strings.Builder
+	for i, n := range modNames {
+		if t.Mods&(1<<i) != 0 {
+			sb.WriteString(n)
+			sb.WriteByte('+')
+		}
+	}
+	sb.WriteString(t.Key.String())
+	return sb.String()
+}
+
+// MarshalText implements encoding.TextMarshaler interface
+func (t Target) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler interface
+func (t *Target) UnmarshalText(text []byte) (err error) {
+	*t, err = Parse(string(text))
+	return
+}
+
+func modByName(name string) Mod {
+	name = strings.TrimSpace(name)
+	for i, n := range modNames {
+		if strings.EqualFold(n, name) {
+			return 1 << i
+		}
+	}
+	return 0
+}
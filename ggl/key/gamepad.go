@@ -0,0 +1,104 @@
+package key
+
+import "github.com/go-gl/glfw/v3.3/glfw"
+
+// Joystick is index of connected joystick, values from Joystick1 to JoystickLast are valid
+type Joystick int
+
+// joystick constants, there can be up to 16 joysticks
+const (
+	Joystick1    = Joystick(glfw.Joystick1)
+	Joystick2    = Joystick1 + 1
+	Joystick3    = Joystick1 + 2
+	Joystick4    = Joystick1 + 3
+	JoystickLast = Joystick(glfw.JoystickLast)
+)
+
+// GamepadButton is button of gamepad in standard layout, layout follows xbox controller
+type GamepadButton int
+
+// all gamepad button constants
+const (
+	GamepadA           = GamepadButton(glfw.ButtonA)           //
+	GamepadB           = GamepadButton(glfw.ButtonB)           //
+	GamepadX           = GamepadButton(glfw.ButtonX)           //
+	GamepadY           = GamepadButton(glfw.ButtonY)           //
+	GamepadLeftBumper  = GamepadButton(glfw.ButtonLeftBumper)  //
+	GamepadRightBumper = GamepadButton(glfw.ButtonRightBumper) //
+	GamepadBack        = GamepadButton(glfw.ButtonBack)        //
+	GamepadStart       = GamepadButton(glfw.ButtonStart)       //
+	GamepadGuide       = GamepadButton(glfw.ButtonGuide)       //
+	GamepadLeftThumb   = GamepadButton(glfw.ButtonLeftThumb)   //
+	GamepadRightThumb  = GamepadButton(glfw.ButtonRightThumb)  //
+	GamepadDpadUp      = GamepadButton(glfw.ButtonDpadUp)      //
+	GamepadDpadRight   = GamepadButton(glfw.ButtonDpadRight)   //
+	GamepadDpadDown    = GamepadButton(glfw.ButtonDpadDown)    //
+	GamepadDpadLeft    = GamepadButton(glfw.ButtonDpadLeft)    //
+	GamepadLast        = GamepadButton(glfw.ButtonLast)        //
+	GamepadCross       = GamepadA                              // playstation alias
+	GamepadCircle      = GamepadB                              // playstation alias
+	GamepadSquare      = GamepadX                              // playstation alias
+	GamepadTriangle    = GamepadY                              // playstation alias
+)
+
+func (b GamepadButton) String() string {
+	val, ok := GamepadNames[b]
+	if !ok {
+		return "Invalid"
+	}
+
+	return val
+}
+
+// GamepadNames is helper for GamepadButton.String() method
+var GamepadNames = map[GamepadButton]string{
+	GamepadA:           "GamepadA",
+	GamepadB:           "GamepadB",
+	GamepadX:           "GamepadX",
+	GamepadY:           "GamepadY",
+	GamepadLeftBumper:  "GamepadLeftBumper",
+	GamepadRightBumper: "GamepadRightBumper",
+	GamepadBack:        "GamepadBack",
+	GamepadStart:       "GamepadStart",
+	GamepadGuide:       "GamepadGuide",
+	GamepadLeftThumb:   "GamepadLeftThumb",
+	GamepadRightThumb:  "GamepadRightThumb",
+	GamepadDpadUp:      "GamepadDpadUp",
+	GamepadDpadRight:   "GamepadDpadRight",
+	GamepadDpadDown:    "GamepadDpadDown",
+	GamepadDpadLeft:    "GamepadDpadLeft",
+}
+
+// Axis is analog axis of gamepad in standard layout, sticks range from -1 to 1 and
+// triggers from -1 (released) to 1 (fully pressed)
+type Axis int
+
+// all axis constants
+const (
+	AxisLeftX        = Axis(glfw.AxisLeftX)        //
+	AxisLeftY        = Axis(glfw.AxisLeftY)        //
+	AxisRightX       = Axis(glfw.AxisRightX)       //
+	AxisRightY       = Axis(glfw.AxisRightY)       //
+	AxisLeftTrigger  = Axis(glfw.AxisLeftTrigger)  //
+	AxisRightTrigger = Axis(glfw.AxisRightTrigger) //
+	AxisLast         = Axis(glfw.AxisLast)         //
+)
+
+func (a Axis) String() string {
+	val, ok := AxisNames[a]
+	if !ok {
+		return "Invalid"
+	}
+
+	return val
+}
+
+// AxisNames is helper for Axis.String() method
+var AxisNames = map[Axis]string{
+	AxisLeftX:        "AxisLeftX",
+	AxisLeftY:        "AxisLeftY",
+	AxisRightX:       "AxisRightX",
+	AxisRightY:       "AxisRightY",
+	AxisLeftTrigger:  "AxisLeftTrigger",
+	AxisRightTrigger: "AxisRightTrigger",
+}
@@ -0,0 +1,60 @@
+package key
+
+import (
+	"strings"
+
+	"github.com/jakubDoka/sterr"
+)
+
+// parsing errors
+var (
+	ErrUnknownKey = sterr.New("unknown key name '%s'")
+)
+
+var (
+	keysByName    = map[string]Key{}
+	gamepadByName = map[string]GamepadButton{}
+	axesByName    = map[string]Axis{}
+)
+
+func init() {
+	for k, n := range Names {
+		keysByName[strings.ToLower(n)] = k
+	}
+	for b, n := range GamepadNames {
+		gamepadByName[strings.ToLower(n)] = b
+	}
+	for a, n := range AxisNames {
+		axesByName[strings.ToLower(n)] = a
+	}
+}
+
+// Parse is inverse of Key.String, name is case insensitive
+//
+//	key.Parse("LeftControl") // key.LeftControl
+//	key.Parse("a")           // key.A
+func Parse(name string) (Key, error) {
+	k, ok := keysByName[strings.ToLower(name)]
+	if !ok {
+		return Unknown, ErrUnknownKey.Args(name)
+	}
+	return k, nil
+}
+
+// ParseGamepad is inverse of GamepadButton.String, name is case insensitive
+func ParseGamepad(name string) (GamepadButton, error) {
+	b, ok := gamepadByName[strings.ToLower(name)]
+	if !ok {
+		return 0, ErrUnknownKey.Args(name)
+	}
+	return b, nil
+}
+
+// ParseAxis is inverse of Axis.String, name is case insensitive
+func ParseAxis(name string) (Axis, error) {
+	a, ok := axesByName[strings.ToLower(name)]
+	if !ok {
+		return 0, ErrUnknownKey.Args(name)
+	}
+	return a, nil
+}
@@ -7,8 +7,11 @@ import (
 	"testing"
 
 	"github.com/jakubDoka/mlok/ggl"
+	"github.com/jakubDoka/mlok/ggl/golden"
 	"github.com/jakubDoka/mlok/mat"
 	"github.com/jakubDoka/mlok/mat/rgba"
+
+	"github.com/jakubDoka/gogen/str"
 )
 
 func TestAtlas(t *testing.T) {
@@ -23,6 +26,23 @@ func TestAtlas(t *testing.T) {
 	}
 }
 
+func TestParagraphGolden(t *testing.T) {
+	p := Paragraph{
+		Tran:    mat.Tran{Scl: mat.V(1, 1)},
+		Mask:    mat.White,
+		Width:   90,
+		Content: str.NString("Hello #ff0000[world]!\nsecond line"),
+	}
+	NMarkdown().Parse(&p)
+	p.SetCenter(mat.ZV)
+	p.Update(0)
+
+	c := golden.Default
+	c.Texture = Atlas7x13.Pic
+	c.Height = 50
+	c.Assert(t, "paragraph", &p.Data)
+}
+
 func TestDrawer(t *testing.T) {
 	win, err := ggl.NWindow(nil)
 	if err != nil {
@@ -0,0 +1,266 @@
+package ui
+
+import (
+	"github.com/jakubDoka/mlok/mat"
+	"github.com/jakubDoka/mlok/mat/lerp"
+)
+
+// Transition describes how element animates when its style changes or when it gets
+// shown or hidden, zero Duration means no animation
+type Transition struct {
+	Duration float64
+	Easing   lerp.Tween
+}
+
+// Easings contains easing curves that can be used in transition style property, you can
+// add your own
+var Easings = map[string]lerp.Tween{
+	"linear":      lerp.Linear(0, 1),
+	"ease":        lerp.Bezier(0, .2, 1, 1),
+	"ease_in":     lerp.Bezier(0, 0, .5, 1),
+	"ease_out":    lerp.Bezier(0, .5, 1, 1),
+	"ease_in_out": lerp.Bezier(0, 0, 1, 1),
+}
+
+// animation is transition in progress
+type animation struct {
+	Transition
+	element  *Element
+	name     string
+	progress float64
+	step     func(t float64)
+	done     func()
+	stopped  bool
+}
+
+// Animate starts animation of element using its Transition, step is called each update with
+// eased progress and done is called when animation ends, done can be nil. Starting animation
+// with same name on same element replaces the old one without calling its done. If element
+// has no transition, step(1) and done are called immediately.
+func (s *Scene) Animate(e *Element, name string, step func(t float64), done func()) {
+	s.StopAnimation(e, name)
+
+	if e.Transition.Duration <= 0 {
+		step(1)
+		if done != nil {
+			done()
+		}
+		return
+	}
+
+	a := &animation{
+		Transition: e.Transition,
+		element:    e,
+		name:       name,
+		step:       step,
+		done:       done,
+	}
+	a.step(a.Easing.Value(0))
+	s.animations = append(s.animations, a)
+}
+
+// StopAnimation stops the animation of given name, returns false if there is no such animation
+func (s *Scene) StopAnimation(e *Element, name string) bool {
+	for i, a := range s.animations {
+		if a.element == e && a.name == name {
+			a.stopped = true
+			s.animations = append(s.animations[:i], s.animations[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// stopAnimations stops all animations of e and its children without calling done,
+// elements waiting to appear are dropped too
+func (s *Scene) stopAnimations(e *Element) {
+	n := 0
+	for _, a := range s.appearing {
+		if !e.isParentOf(a) {
+			s.appearing[n] = a
+			n++
+		}
+	}
+	s.appearing = s.appearing[:n]
+
+	n = 0
+	for _, a := range s.animations {
+		if e.isParentOf(a.element) {
+			a.stopped = true
+			continue
+		}
+		s.animations[n] = a
+		n++
+	}
+	s.animations = s.animations[:n]
+}
+
+// Animating returns whether there is any animation in progress
+func (s *Scene) Animating() bool {
+	return len(s.animations) != 0
+}
+
+// animate advances all animations
+func (s *Scene) animate(delta float64) {
+	// animations can start other animations so we have to copy them
+	s.stepping = append(s.stepping[:0], s.animations...)
+	for _, a := range s.stepping {
+		if a.stopped {
+			continue // stopped by step of other animation
+		}
+		a.progress += delta / a.Duration
+		if a.progress < 1 {
+			a.step(a.Easing.Value(a.progress))
+			continue
+		}
+
+		if !s.StopAnimation(a.element, a.name) {
+			continue // replaced by other animation
+		}
+		a.step(a.Easing.Value(1))
+		if a.done != nil {
+			a.done()
+		}
+	}
+}
+
+// layoutProps are properties that transition animates when style changes
+type layoutProps struct {
+	Margin, Padding mat.AABB
+	Size, Offest    mat.Vec
+	Resizing        [2]ResizeMode
+}
+
+// layout returns properties related to layout
+func (p *Props) layout() layoutProps {
+	return layoutProps{p.Margin, p.Padding, p.Size, p.Offest, p.Resizing}
+}
+
+// setLayout sets properties related to layout
+func (p *Props) setLayout(l layoutProps) {
+	p.Margin, p.Padding, p.Size, p.Offest, p.Resizing = l.Margin, l.Padding, l.Size, l.Offest, l.Resizing
+}
+
+// lerp interpolates layout properties, resizing is switched right away as it cannot be
+// interpolated
+func (l layoutProps) lerp(b layoutProps, t float64) layoutProps {
+	return layoutProps{
+		Margin:   lerpAABB(l.Margin, b.Margin, t),
+		Padding:  lerpAABB(l.Padding, b.Padding, t),
+		Size:     mat.V(lerpFill(l.Size.X, b.Size.X, t), lerpFill(l.Size.Y, b.Size.Y, t)),
+		Offest:   l.Offest.Lerp(b.Offest, t),
+		Resizing: b.Resizing,
+	}
+}
+
+// transitionStyle animates layout properties from old values if they changed
+func (e *Element) transitionStyle(old layoutProps) {
+	to := e.layout()
+	if to == old || e.collapse != nil {
+		return
+	}
+
+	e.Scene.Animate(e, "style", func(t float64) {
+		e.setLayout(old.lerp(to, t))
+		e.Scene.Resize.Notify()
+	}, nil)
+}
+
+// collapse holds state of element that is being shown or hidden with animation
+type collapse struct {
+	saved  layoutProps
+	hiding bool
+}
+
+// setHiddenAnimated shows or hides element with animation, when element hides, its size,
+// margin and padding shrink to zero, showing does the opposite, though size element grows
+// to is known only after next resize
+func (e *Element) setHiddenAnimated(value bool) {
+	if e.Hidden() == value {
+		return
+	}
+
+	saved := e.layout()
+	if e.collapse != nil {
+		saved = e.collapse.saved
+	}
+
+	if !value {
+		e.Scene.StopAnimation(e, "visibility")
+		e.setLayout(saved)
+		e.collapse = &collapse{saved: saved}
+		e.hidden = false
+		e.onHiddenChange()
+		e.Scene.appearing = append(e.Scene.appearing, e)
+		return
+	}
+
+	from := e.natural()
+	to := layoutProps{Offest: saved.Offest, Resizing: from.Resizing}
+	e.collapse = &collapse{saved: saved, hiding: true}
+	e.Scene.Animate(e, "visibility", func(t float64) {
+		e.setLayout(from.lerp(to, t))
+		e.Scene.Resize.Notify()
+	}, func() {
+		e.setLayout(saved)
+		e.collapse = nil
+		e.hidden = true
+		e.onHiddenChange()
+	})
+}
+
+// appear starts the animation of element that was just shown and resized
+func (e *Element) appear() {
+	if e.collapse == nil || e.collapse.hiding {
+		return
+	}
+
+	saved := e.collapse.saved
+	to := e.natural()
+	from := layoutProps{Offest: saved.Offest, Resizing: to.Resizing}
+	e.Scene.Animate(e, "visibility", func(t float64) {
+		e.setLayout(from.lerp(to, t))
+		e.Scene.Resize.Notify()
+	}, func() {
+		e.setLayout(saved)
+		e.collapse = nil
+	})
+}
+
+// natural returns layout that keeps element at its current size
+func (e *Element) natural() layoutProps {
+	l := e.layout()
+	l.Margin = e.margin
+	l.Size = e.size.Sub(e.PaddingSize())
+	l.Resizing = [2]ResizeMode{Ignore, Ignore}
+	return l
+}
+
+// transitionBackground animates background color from old value
+func (m *ModuleBase) transitionBackground(old mat.RGBA) {
+	to := m.Background
+	if to == old {
+		return
+	}
+
+	m.Scene.Animate(m.Element, "background", func(t float64) {
+		m.Background = mat.LerpColor(old, to, t)
+		m.Scene.Redraw.Notify()
+	}, nil)
+}
+
+func lerpAABB(a, b mat.AABB, t float64) mat.AABB {
+	return mat.AABB{
+		Min: mat.V(lerpFill(a.Min.X, b.Min.X, t), lerpFill(a.Min.Y, b.Min.Y, t)),
+		Max: mat.V(lerpFill(a.Max.X, b.Max.X, t), lerpFill(a.Max.Y, b.Max.Y, t)),
+	}
+}
+
+// lerpFill interpolates floats, if one of them is Fill, b is returned as it does not
+// make sense to interpolate it
+func lerpFill(a, b, t float64) float64 {
+	if a == Fill || b == Fill {
+		return b
+	}
+	return mat.Lerp(a, b, t)
+}
@@ -0,0 +1,328 @@
+package ui
+
+import (
+	"reflect"
+
+	"github.com/jakubDoka/mlok/refl"
+	"github.com/jakubDoka/sterr"
+)
+
+// Binding related errors
+var (
+	ErrNotBindable = sterr.New("module of type %T cannot be bound, it does not implement Bindable")
+	ErrNotSettable = sterr.New("field at '%s' cannot be set, it has to be reachable trough pointer and cannot be in map")
+	ErrBinding     = sterr.New("binding to '%s' failed")
+)
+
+// Bindable can be implemented by Module so it can be bound to data with "bind" attribute
+type Bindable interface {
+	// BindValue returns value module displays, type of returned value also determinate
+	// type that data gets converted to before it is passed to SetBindValue
+	BindValue() interface{}
+	// SetBindValue sets the value module displays, it should not invoke any events
+	SetBindValue(value interface{})
+	// BindEvent returns event after witch value is written back to data, empty string
+	// makes binding one-way
+	BindEvent() string
+}
+
+// Binding connects element value to field of data bound to Scene, bindings are created
+// from "bind" and "bind_hidden" attributes. Each Processor.Update values of fields are
+// compared with values from previous update and element is updated if they differ.
+// Writing to field happens when Bindable.BindEvent is invoked on element. Path that cannot
+// be resolved is reported once and binding is skipped until Scene.Bind or Element.SetContext
+// is called again.
+type Binding struct {
+	Element *Element
+	Path    string
+
+	get  func() interface{}
+	set  func(interface{})
+	last interface{}
+
+	synced, failed bool
+}
+
+// Bind binds data to the scene, data should be pointer to struct so fields can be set by
+// two-way bindings. All bindings are synced immediately.
+//
+//	type Player struct{ Name string }
+//	type State struct{ Player Player }
+//	...
+//	scene.Bind(&state) // <area bind="Player.Name"/> now edits state.Player.Name
+func (s *Scene) Bind(data interface{}) {
+	s.data = data
+	for _, bs := range s.bindings {
+		for _, b := range bs {
+			b.synced, b.failed = false, false
+		}
+	}
+	s.Sync()
+}
+
+// Data returns data bound to the scene
+func (s *Scene) Data() interface{} {
+	return s.data
+}
+
+// Sync updates elements whose bound fields changed, this is called by Processor.Update so
+// calling it manually is needed only if you change data between updates and want to see
+// it immediately
+func (s *Scene) Sync() {
+	for e, bs := range s.bindings {
+		if e.Scene != s {
+			delete(s.bindings, e)
+			continue
+		}
+		data := e.Context()
+		if data == nil {
+			continue
+		}
+		for _, b := range bs {
+			s.Log(e, b.pull(data))
+		}
+	}
+}
+
+// addBindings creates bindings from element attributes, bindings are created only once
+// per element
+func (s *Scene) addBindings(e *Element) {
+	if e.bindings == nil {
+		if path := e.Raw.Attributes.Ident("bind", ""); path != "" {
+			if bd, ok := e.Module.(Bindable); ok {
+				e.bind(path, bd.BindEvent(), bd.BindValue, bd.SetBindValue)
+			} else {
+				s.Log(e, ErrNotBindable.Args(e.Module))
+			}
+		}
+
+		if path := e.Raw.Attributes.Ident("bind_hidden", ""); path != "" {
+			e.bind(path, "", func() interface{} {
+				return e.hidden
+			}, func(v interface{}) {
+				e.SetHidden(v.(bool))
+			})
+		}
+	}
+
+	if len(e.bindings) == 0 {
+		return
+	}
+
+	if s.bindings == nil {
+		s.bindings = map[*Element][]*Binding{}
+	}
+	s.bindings[e] = e.bindings
+	e.pullBindings()
+}
+
+// SetContext sets data that bindings of element and its children resolve paths from,
+// element without context uses context of its parent and root uses data bound to the
+// scene. Bindings are synced immediately.
+func (e *Element) SetContext(data interface{}) {
+	e.context = data
+	var pull func(e *Element)
+	pull = func(e *Element) {
+		e.pullBindings()
+		e.forChild(FCfg{}, pull)
+	}
+	pull(e)
+}
+
+// Context returns data bindings of element resolve paths from
+func (e *Element) Context() interface{} {
+	for p := e; p != nil; p = p.Parent {
+		if p.context != nil {
+			return p.context
+		}
+	}
+	if e.Scene != nil {
+		return e.Scene.data
+	}
+	return nil
+}
+
+// pullBindings forces bindings of element to sync
+func (e *Element) pullBindings() {
+	if e.Scene == nil {
+		return
+	}
+	data := e.Context()
+	for _, b := range e.bindings {
+		b.synced, b.failed = false, false
+		if data != nil {
+			e.Scene.Log(e, b.pull(data))
+		}
+	}
+}
+
+// bind creates binding and hooks it to event if it is not empty
+func (e *Element) bind(path, ev string, get func() interface{}, set func(interface{})) {
+	b := &Binding{
+		Element: e,
+		Path:    path,
+		get:     get,
+		set:     set,
+	}
+	e.bindings = append(e.bindings, b)
+
+	if ev != "" {
+		e.Listen(ev, func(interface{}) {
+			if data := e.Context(); data != nil && e.Scene != nil {
+				e.Scene.Log(e, b.push(data))
+			}
+		})
+	}
+}
+
+// pull updates element if field changed
+func (b *Binding) pull(data interface{}) error {
+	if b.failed {
+		return nil
+	}
+
+	field, err := refl.Path(reflect.ValueOf(data), b.Path)
+	if err != nil {
+		b.failed = true
+		return ErrBinding.Args(b.Path).Wrap(err)
+	}
+
+	current := field.Interface()
+	if b.synced && reflect.DeepEqual(current, b.last) {
+		return nil
+	}
+	b.last = current
+	b.synced = true
+
+	value := reflect.New(reflect.TypeOf(b.get())).Elem()
+	if err := refl.Assign(value, field); err != nil {
+		return ErrBinding.Args(b.Path).Wrap(err)
+	}
+	b.set(value.Interface())
+
+	return nil
+}
+
+// push writes element value to field
+func (b *Binding) push(data interface{}) error {
+	field, err := refl.Path(reflect.ValueOf(data), b.Path)
+	if err != nil {
+		return ErrBinding.Args(b.Path).Wrap(err)
+	}
+
+	if !field.CanSet() {
+		return ErrNotSettable.Args(b.Path)
+	}
+
+	if err := refl.Assign(field, reflect.ValueOf(b.get())); err != nil {
+		return ErrBinding.Args(b.Path).Wrap(err)
+	}
+	b.last = field.Interface()
+
+	return nil
+}
+
+// BindValue implements Bindable interface
+func (t *Text) BindValue() interface{} {
+	return string(t.Content)
+}
+
+// SetBindValue implements Bindable interface
+func (t *Text) SetBindValue(value interface{}) {
+	t.SetText(value.(string))
+}
+
+// BindEvent implements Bindable interface, text is only displayed so binding is one-way
+func (t *Text) BindEvent() string {
+	return ""
+}
+
+// BindEvent implements Bindable interface, data is updated on each edit
+func (a *Area) BindEvent() string {
+	return TextChanged
+}
+
+// BindValue implements Bindable interface
+func (b *Button) BindValue() interface{} {
+	return string(b.States[b.Current].Text)
+}
+
+// SetBindValue implements Bindable interface, it sets text of all states
+func (b *Button) SetBindValue(value interface{}) {
+	b.SetText(value.(string))
+	b.reapply()
+}
+
+// BindEvent implements Bindable interface
+func (b *Button) BindEvent() string {
+	return ""
+}
+
+// BindValue implements Bindable interface
+func (c *Checkbox) BindValue() interface{} {
+	return c.Checked
+}
+
+// SetBindValue implements Bindable interface
+func (c *Checkbox) SetBindValue(value interface{}) {
+	c.SetChecked(value.(bool))
+}
+
+// BindEvent implements Bindable interface
+func (c *Checkbox) BindEvent() string {
+	return Changed
+}
+
+// BindValue implements Bindable interface, value is name of selected child
+func (r *RadioGroup) BindValue() interface{} {
+	return r.Selected
+}
+
+// SetBindValue implements Bindable interface
+func (r *RadioGroup) SetBindValue(value interface{}) {
+	r.Selected = value.(string)
+	r.apply()
+}
+
+// BindEvent implements Bindable interface
+func (r *RadioGroup) BindEvent() string {
+	return Changed
+}
+
+// BindValue implements Bindable interface
+func (s *Slider) BindValue() interface{} {
+	return s.Value
+}
+
+// SetBindValue implements Bindable interface
+func (s *Slider) SetBindValue(value interface{}) {
+	s.Value = s.clamp(value.(float64))
+	s.OnFrameChange()
+	s.Scene.Redraw.Notify()
+}
+
+// BindEvent implements Bindable interface
+func (s *Slider) BindEvent() string {
+	return Changed
+}
+
+// BindValue implements Bindable interface, value is selected option
+func (d *Dropdown) BindValue() interface{} {
+	return d.Value()
+}
+
+// SetBindValue implements Bindable interface, nothing is selected if option does not exist
+func (d *Dropdown) SetBindValue(value interface{}) {
+	d.Selected = -1
+	for i, o := range d.Options {
+		if o == value.(string) {
+			d.Selected = i
+		}
+	}
+	d.showSelected()
+}
+
+// BindEvent implements Bindable interface
+func (d *Dropdown) BindEvent() string {
+	return Changed
+}
@@ -0,0 +1,186 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/jakubDoka/goml"
+	"github.com/jakubDoka/goml/goss"
+	"github.com/jakubDoka/sterr"
+)
+
+// component errors
+var (
+	ErrComponent          = sterr.New("component %s has to have name and exactly one root element")
+	ErrComponentRecursion = sterr.New("component %s contains itself")
+)
+
+// Component is element template defined in goml. Attributes of component definition are
+// its parameters with default values, they can be referenced in attributes of template by
+// $ prefix. Children of instance are placed where slot element is:
+//
+//	<component name="dialog" title="Dialog" ok="Ok">
+//		<div styles="dialog">
+//			<text styles="dialog_title" text="$title"/>
+//			<div name="content">
+//				<slot/>
+//			</>
+//			<button name="ok"><text text="$ok"/></>
+//		</>
+//	</>
+//
+// Definitions are registered when goml containing them is parsed, they are not turned into
+// elements and have to be parsed before goml that uses them:
+//
+//	<dialog name="quit" title="Quit?" styles="small">
+//		<text text="Unsaved progress will be lost."/>
+//	</>
+//
+// Attributes of instance that are not parameters are put on root of template, styles are
+// appended to styles of the root and style overwrites its style. Parameters cannot be used
+// inside style attribute as it is parsed by goss together with goml, pass styles names as
+// parameters instead:
+//
+//	<component name="card" look="card_default">
+//		<div styles="$look"/>
+//	</>
+//
+// Reference to name that is not a parameter stays as it is.
+type Component struct {
+	Name     string
+	Params   goml.Attribs
+	Template goml.Element
+}
+
+// AddComponent registers component, elements with component name are then expanded into
+// its template
+func (p *Parser) AddComponent(c Component) {
+	p.components[c.Name] = c
+	p.GP.AddDefinitions(c.Name)
+}
+
+// Component returns component under the name
+func (p *Parser) Component(name string) (Component, bool) {
+	c, ok := p.components[name]
+	return c, ok
+}
+
+// addComponent registers component from goml definition
+func (p *Parser) addComponent(def goml.Element) error {
+	name := def.Attributes.Ident("name", "")
+	if name == "" || len(def.Children) != 1 {
+		return ErrComponent.Args(name)
+	}
+
+	params := goml.Attribs{}
+	for k, v := range def.Attributes {
+		if k != "name" {
+			params[k] = v
+		}
+	}
+
+	p.AddComponent(Component{name, params, def.Children[0]})
+	return nil
+}
+
+// slotContent is content that replaces slot of component that is being expanded, outer is
+// content of component that contains the instance
+type slotContent struct {
+	component string
+	children  []goml.Element
+	outer     *slotContent
+}
+
+// expand translates instance of component
+func (p *Parser) expand(i int, c Component, instance goml.Element, slot *slotContent) (*Element, error) {
+	for s := slot; s != nil; s = s.outer {
+		if s.component == c.Name {
+			return nil, ErrComponentRecursion.Args(c.Name)
+		}
+	}
+
+	params := goml.Attribs{}
+	for k, v := range c.Params {
+		params[k] = v
+	}
+	for k, v := range instance.Attributes {
+		if _, ok := c.Params[k]; ok {
+			params[k] = v
+		}
+	}
+
+	root := p.substitute(c.Template, params)
+	if _, ok := instance.Attributes["name"]; !ok {
+		delete(root.Attributes, "name")
+	}
+	for k, v := range instance.Attributes {
+		if _, ok := c.Params[k]; ok {
+			continue
+		}
+		if k == "styles" {
+			v = []string{strings.Join(append(append([]string{}, root.Attributes[k]...), v...), " ")}
+		}
+		root.Attributes[k] = v
+	}
+	if instance.Style != nil {
+		if root.Style == nil {
+			root.Style = goss.Style{}
+		}
+		instance.Style.Overwrite(root.Style)
+	}
+
+	return p.translate(i, root, &slotContent{c.Name, instance.Children, slot})
+}
+
+// substitute returns copy of template with parameters replaced by values
+func (p *Parser) substitute(e goml.Element, params goml.Attribs) goml.Element {
+	cp := goml.Element{Name: e.Name, Attributes: goml.Attribs{}}
+	for k, v := range e.Attributes {
+		nv := make([]string, len(v))
+		for i, s := range v {
+			nv[i] = replaceParams(s, params)
+		}
+		cp.Attributes[k] = nv
+	}
+
+	// style is parsed by goss along with goml so it cannot contain parameters
+	if e.Style != nil {
+		cp.Style = goss.Style{}
+		e.Style.Overwrite(cp.Style)
+	}
+
+	cp.Children = make([]goml.Element, len(e.Children))
+	for i, ch := range e.Children {
+		cp.Children[i] = p.substitute(ch, params)
+	}
+
+	return cp
+}
+
+// replaceParams replaces parameter references in s, references to missing parameters are
+// kept
+func replaceParams(s string, params goml.Attribs) string {
+	if !strings.Contains(s, "$") {
+		return s
+	}
+
+	var sb strings.Builder
+	for {
+		i := strings.IndexByte(s, '$')
+		if i == -1 {
+			sb.WriteString(s)
+			return sb.String()
+		}
+		sb.WriteString(s[:i])
+
+		j := i + 1
+		for j < len(s) && (s[j] == '_' || s[j] >= 'a' && s[j] <= 'z' || s[j] >= 'A' && s[j] <= 'Z' || s[j] >= '0' && s[j] <= '9') {
+			j++
+		}
+		if v, ok := params[s[i+1:j]]; ok {
+			sb.WriteString(strings.Join(v, " "))
+		} else {
+			sb.WriteString(s[i:j])
+		}
+		s = s[j:]
+	}
+}
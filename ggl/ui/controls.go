@@ -0,0 +1,467 @@
+package ui
+
+import (
+	"math"
+
+	"github.com/jakubDoka/mlok/ggl"
+	"github.com/jakubDoka/mlok/ggl/drw"
+	"github.com/jakubDoka/mlok/ggl/input"
+	"github.com/jakubDoka/mlok/ggl/key"
+	"github.com/jakubDoka/mlok/mat"
+
+	"github.com/jakubDoka/goml/goss"
+)
+
+// Checkbox is a Button that toggles between checked and unchecked state. Checked state has
+// its own ButtonStates, keys are same as in Button but prefixed with "checked_" and values
+// default to unchecked states. When toggled, Changed event is invoked with new bool value.
+//
+// style:
+//
+//	checked:	bool	// initial state
+//
+//	<checkbox all_text="fullscreen" checked_all_masks="green"/>
+type Checkbox struct {
+	Button
+
+	Unchecked, CheckedStates ButtonStates
+	Checked                  bool
+
+	group *RadioGroup
+}
+
+// New implements ModuleFactory interface
+func (c *Checkbox) New() Module {
+	return &Checkbox{}
+}
+
+// Init implements Module interface
+func (c *Checkbox) Init(e *Element) {
+	c.Button.Init(e)
+	c.Checked = e.Bool("checked", c.Checked)
+	c.onClick = c.clicked
+}
+
+// PostInit implements Module interface
+func (c *Checkbox) PostInit() {
+	c.Button.PostInit()
+	c.Unchecked = c.States
+	c.CheckedStates = ParseButtonStates(c.Element, "checked_", &c.Unchecked)
+	c.SetChecked(c.Checked)
+}
+
+// SetChecked sets the state without invoking an event
+func (c *Checkbox) SetChecked(value bool) {
+	c.Checked = value
+	if value {
+		c.States = c.CheckedStates
+	} else {
+		c.States = c.Unchecked
+	}
+
+	c.reapply()
+}
+
+// Toggle switches the state and invokes Changed event
+func (c *Checkbox) Toggle() {
+	c.SetChecked(!c.Checked)
+	c.Events.Invoke(Changed, c.Checked)
+}
+
+func (c *Checkbox) clicked() {
+	if c.group != nil {
+		c.group.Select(c.Name())
+	} else {
+		c.Toggle()
+	}
+}
+
+// RadioGroup makes its Checkbox children mutually exclusive, only one can be checked at
+// the time and clicking on checked one does nothing. Selected is name of checked child.
+// When selection changes, Changed event is invoked on group with name of selected child.
+//
+//	<radio_group>
+//		<checkbox name="easy" all_text="easy" style="checked: true;"/>
+//		<checkbox name="hard" all_text="hard"/>
+//	</>
+type RadioGroup struct {
+	ModuleBase
+
+	Selected string
+}
+
+// New implements ModuleFactory interface
+func (r *RadioGroup) New() Module {
+	return &RadioGroup{}
+}
+
+// PostInit implements Module interface
+func (r *RadioGroup) PostInit() {
+	r.ForChild(func(ch *Element) {
+		c, ok := ch.Module.(*Checkbox)
+		if !ok {
+			return
+		}
+		c.group = r
+		if c.Checked && r.Selected == "" {
+			r.Selected = ch.Name()
+		}
+	})
+	r.apply()
+}
+
+// Select checks child with given name and unchecks others, nothing happens if
+// child is already selected
+func (r *RadioGroup) Select(name string) {
+	if r.Selected == name {
+		return
+	}
+	r.Selected = name
+	r.apply()
+	r.Events.Invoke(Changed, name)
+}
+
+func (r *RadioGroup) apply() {
+	r.ForChild(func(ch *Element) {
+		if c, ok := ch.Module.(*Checkbox); ok {
+			c.SetChecked(ch.Name() == r.Selected)
+		}
+	})
+}
+
+// Slider allows picking a number from range by dragging the handle or with arrow keys if
+// slider is focused. Handle is styled like Button but all keys are prefixed with "handle_",
+// text is ignored. When value changes, Changed event is invoked with float64 value.
+//
+// style:
+//
+//	slider_min/slider_max:	float	// range of value, default is 0 1
+//	slider_step:			float	// value snaps to multiples of step, 0 means no snapping
+//	slider_value:			float	// initial value
+//	slider_vertical:		bool	// makes slider vertical, minimum is at the bottom
+//	handle_size:			vec		// size of handle
+//	rail_thickness:			float	// thickness of a line handle moves along
+//	rail_color:				rgba	// color of a rail
+type Slider struct {
+	ModuleBase
+	Handle ggl.Sprite
+
+	States  ButtonStates
+	Current ButtonStateEnum
+
+	Min, Max, Step, Value float64
+	Vertical, Disabled    bool
+
+	HandleSize    mat.Vec
+	RailThickness float64
+	RailColor     mat.RGBA
+
+	dragging bool
+}
+
+// New implements ModuleFactory interface
+func (s *Slider) New() Module {
+	return &Slider{}
+}
+
+// DefaultStyle implements Module interface
+func (s *Slider) DefaultStyle() goss.Style {
+	return goss.Style{
+		"tab_index": {0},
+	}
+}
+
+// Init implements Module interface
+func (s *Slider) Init(e *Element) {
+	s.ModuleBase.Init(e)
+	s.States = ParseButtonStates(e, "handle_", nil)
+	s.Min = e.Float("slider_min", 0)
+	s.Max = e.Float("slider_max", 1)
+	s.Step = e.Float("slider_step", 0)
+	s.Vertical = e.Bool("slider_vertical", false)
+	s.HandleSize = e.Vec("handle_size", mat.V(10, 20))
+	s.RailThickness = e.Float("rail_thickness", 4)
+	s.RailColor = e.RGBA("rail_color", mat.Alpha(.5))
+
+	s.Value = s.clamp(e.Float("slider_value", s.Value))
+	s.Current = None
+	s.ApplyState(Idle)
+}
+
+// Update implements Module interface
+func (s *Slider) Update(w input.Input, delta float64) {
+	if s.Disabled {
+		s.dragging = false
+		s.ApplyState(Disabled)
+		return
+	}
+
+	if s.Hovering && w.JustPressed(key.MouseLeft) {
+		s.dragging = true
+	}
+	if w.JustReleased(key.MouseLeft) {
+		s.dragging = false
+	}
+
+	if s.dragging {
+		s.SetValue(s.project(w.MousePos()))
+	}
+
+	if s.Focused() {
+		step := s.Step
+		if step == 0 {
+			step = (s.Max - s.Min) * .1
+		}
+		less, more := key.Left, key.Right
+		if s.Vertical {
+			less, more = key.Down, key.Up
+		}
+		if w.JustPressed(less) || w.Repeated(less) {
+			s.SetValue(s.Value - step)
+		}
+		if w.JustPressed(more) || w.Repeated(more) {
+			s.SetValue(s.Value + step)
+		}
+	}
+
+	switch {
+	case s.dragging:
+		s.ApplyState(Pressed)
+	case s.Hovering || s.Focused():
+		s.ApplyState(Hover)
+	default:
+		s.ApplyState(Idle)
+	}
+}
+
+// Captures implements KeyCapturer interface, arrows along the slider change the value
+func (s *Slider) Captures(k key.Key) bool {
+	if s.Vertical {
+		return k == key.Up || k == key.Down
+	}
+	return k == key.Left || k == key.Right
+}
+
+// SetValue sets the value, value is clamped and snapped to step, Changed event is invoked
+// only if value really changes
+func (s *Slider) SetValue(value float64) {
+	value = s.clamp(value)
+	if value == s.Value {
+		return
+	}
+	s.Value = value
+	s.OnFrameChange()
+	s.Scene.Redraw.Notify()
+	s.Events.Invoke(Changed, value)
+}
+
+// Ratio returns where value is in range, 0 is minimum and 1 is maximum
+func (s *Slider) Ratio() float64 {
+	if s.Max == s.Min {
+		return 0
+	}
+	return (s.Value - s.Min) / (s.Max - s.Min)
+}
+
+// ApplyState applies the handle state
+func (s *Slider) ApplyState(state ButtonStateEnum) {
+	if s.Current == state {
+		return
+	}
+	s.Current = state
+	bs := &s.States[state]
+	if bs.Region == mat.ZA {
+		s.Handle = ggl.Sprite{}
+		s.Handle.SetIntensity(0)
+	} else {
+		s.Handle = ggl.NSprite(bs.Region)
+	}
+	s.Handle.SetColor(bs.Mask)
+	s.OnFrameChange()
+	if s.Scene != nil {
+		s.Scene.Redraw.Notify()
+	}
+}
+
+// OnFrameChange implements Module interface
+func (s *Slider) OnFrameChange() {
+	s.Handle.SetDist(mat.Centered(s.handlePos(), s.HandleSize.X, s.HandleSize.Y))
+}
+
+// Draw implements Module interface
+func (s *Slider) Draw(t ggl.Target, g *drw.Geom) {
+	s.ModuleBase.Draw(t, g)
+	g.Clear()
+
+	c := s.Frame.Center()
+	rail := mat.A(s.Frame.Min.X, c.Y-s.RailThickness*.5, s.Frame.Max.X, c.Y+s.RailThickness*.5)
+	if s.Vertical {
+		rail = mat.A(c.X-s.RailThickness*.5, s.Frame.Min.Y, c.X+s.RailThickness*.5, s.Frame.Max.Y)
+	}
+	g.Color(s.RailColor).AABB(rail)
+	g.Fetch(t)
+
+	s.Handle.Fetch(t)
+}
+
+// handlePos returns center of handle
+func (s *Slider) handlePos() mat.Vec {
+	c := s.Frame.Center()
+	if s.Vertical {
+		min, max := s.Frame.Min.Y+s.HandleSize.Y*.5, s.Frame.Max.Y-s.HandleSize.Y*.5
+		c.Y = mat.Lerp(min, max, s.Ratio())
+	} else {
+		min, max := s.Frame.Min.X+s.HandleSize.X*.5, s.Frame.Max.X-s.HandleSize.X*.5
+		c.X = mat.Lerp(min, max, s.Ratio())
+	}
+	return c
+}
+
+// project returns value corresponding to position
+func (s *Slider) project(pos mat.Vec) float64 {
+	var t float64
+	if s.Vertical {
+		min, max := s.Frame.Min.Y+s.HandleSize.Y*.5, s.Frame.Max.Y-s.HandleSize.Y*.5
+		t = (pos.Y - min) / (max - min)
+	} else {
+		min, max := s.Frame.Min.X+s.HandleSize.X*.5, s.Frame.Max.X-s.HandleSize.X*.5
+		t = (pos.X - min) / (max - min)
+	}
+	if math.IsNaN(t) {
+		t = 0
+	}
+	return s.Min + (s.Max-s.Min)*t
+}
+
+// clamp snaps value to step and clamps it into range
+func (s *Slider) clamp(value float64) float64 {
+	if s.Step > 0 {
+		value = s.Min + math.Round((value-s.Min)/s.Step)*s.Step
+	}
+	return mat.Clamp(value, math.Min(s.Min, s.Max), math.Max(s.Min, s.Max))
+}
+
+// Dropdown is a Button that opens list of options when clicked. Options are Buttons styled
+// by "option_styles" attribute, list is relative child of dropdown so it does not affect
+// the layout, it is positioned under the dropdown. When option is picked, list closes,
+// dropdown displays the option and Changed event is invoked with option string. Clicking
+// outside closes the list.
+//
+// attributes:
+//
+//	options:		list of strings		// options to choose from
+//	selected:		string				// initially selected option
+//	option_styles:	list of strings		// styles used by options
+//
+//	<dropdown options=["low" "medium" "high"] selected="medium" option_styles="option"/>
+type Dropdown struct {
+	Button
+
+	Options  []string
+	Selected int
+	List     *Element
+}
+
+// New implements ModuleFactory interface
+func (d *Dropdown) New() Module {
+	return &Dropdown{}
+}
+
+// Init implements Module interface
+func (d *Dropdown) Init(e *Element) {
+	d.Button.Init(e)
+	if d.Options != nil {
+		return // reloading style should not change the selection
+	}
+	d.Options = e.Raw.Attributes["options"]
+	d.onClick = d.Toggle
+
+	selected := e.Raw.Attributes.Ident("selected", "")
+	for i, o := range d.Options {
+		if o == selected {
+			d.Selected = i
+		}
+	}
+}
+
+// PostInit implements Module interface
+func (d *Dropdown) PostInit() {
+	d.Button.PostInit()
+
+	if list, ok := d.Child("dropdownList"); ok {
+		d.RemoveChild(list.Name())
+	}
+
+	d.List = NElement()
+	d.List.Raw.Style = goss.Style{"relative": {true}}
+	d.List.hidden = true
+	styles := d.Raw.Attributes["option_styles"]
+	for i, o := range d.Options {
+		i := i
+		opt := NElement()
+		btn := &Button{onClick: func() { d.Select(i) }}
+		opt.Module = btn
+		opt.Raw.Attributes = map[string][]string{"all_text": {o}}
+		opt.Styles = styles
+		d.List.AddChild(o, opt)
+	}
+	d.AddChild("dropdownList", d.List)
+
+	d.showSelected()
+}
+
+// Update implements Module interface
+func (d *Dropdown) Update(w input.Input, delta float64) {
+	if !d.List.Hidden() && w.JustPressed(key.MouseLeft) && !d.Hovering && !d.List.Hovering {
+		d.Close()
+	}
+	d.Button.Update(w, delta)
+}
+
+// OnFrameChange implements Module interface
+func (d *Dropdown) OnFrameChange() {
+	d.Button.OnFrameChange()
+	if d.List != nil {
+		pad, mar := d.Props.Padding.Min, d.List.margin.Min
+		d.List.Offest = mat.V(-pad.X-mar.X, -pad.Y-mar.Y-d.List.size.Y)
+	}
+}
+
+// Toggle opens or closes the list
+func (d *Dropdown) Toggle() {
+	d.List.SetHidden(!d.List.Hidden())
+}
+
+// Close closes the list
+func (d *Dropdown) Close() {
+	if !d.List.Hidden() {
+		d.List.SetHidden(true)
+	}
+}
+
+// Select selects option by index and closes the list, Changed event is invoked
+func (d *Dropdown) Select(index int) {
+	d.Close()
+	if index == d.Selected {
+		return
+	}
+	d.Selected = index
+	d.showSelected()
+	d.Events.Invoke(Changed, d.Value())
+}
+
+// Value returns selected option, or empty string if there are no options
+func (d *Dropdown) Value() string {
+	if d.Selected < 0 || d.Selected >= len(d.Options) {
+		return ""
+	}
+	return d.Options[d.Selected]
+}
+
+func (d *Dropdown) showSelected() {
+	if len(d.Options) == 0 {
+		return
+	}
+	d.SetText(d.Value())
+	d.reapply()
+}
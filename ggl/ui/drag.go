@@ -0,0 +1,207 @@
+package ui
+
+import (
+	"github.com/jakubDoka/mlok/ggl"
+	"github.com/jakubDoka/mlok/ggl/drw"
+	"github.com/jakubDoka/mlok/ggl/input"
+	"github.com/jakubDoka/mlok/ggl/key"
+	"github.com/jakubDoka/mlok/mat"
+)
+
+// drag and drop defaults
+var (
+	// DragThreshold is distance mouse has to travel with pressed button until drag starts
+	DragThreshold = 4.0
+	// DefaultGhostMask is default of Scene.GhostMask
+	DefaultGhostMask = mat.Alpha(.5)
+)
+
+// DragEvent is data passed to drag events. Element with draggable style can be picked up
+// by the left mouse button, while it is dragged, its ghost follows the mouse and elements
+// with drop_target style receive the events:
+//
+//	DragStart	// invoked on Source, listener can set the Payload or Reject the drag
+//	DragEnter	// invoked on Target when mouse enters it, Reject makes Target refuse the drop
+//	DragLeave	// invoked on Target when mouse leaves it or drag ends without drop
+//	Drop		// invoked on Target that did not refuse the drop, Reject cancels the drop
+//	DragEnd		// invoked on Source after drag ended, it is rejected if drop did not happen
+//
+// Payload is the Source by default. Reorderable list can be done like:
+//
+//	item.Listen(ui.Drop, func(i interface{}) {
+//		ev := i.(*ui.DragEvent)
+//		ev.Source.SetIndex(ev.Target.Index())
+//	})
+//
+// style:
+//
+//	draggable:		bool	// element can be dragged
+//	drop_target:	bool	// element receives drag events
+type DragEvent struct {
+	Source, Target *Element
+	Payload        interface{}
+	Pos            mat.Vec
+
+	rejected bool
+}
+
+// Reject rejects the drag, drop or drop target depending on event
+func (d *DragEvent) Reject() {
+	d.rejected = true
+}
+
+// Rejected returns whether Reject was called
+func (d *DragEvent) Rejected() bool {
+	return d.rejected
+}
+
+// drag is state of drag and drop
+type drag struct {
+	source, target   *Element
+	active, accepted bool
+	payload          interface{}
+	start, pos       mat.Vec
+	ghost            ghost
+}
+
+// Dragged returns element that is being dragged or nil
+func (s *Scene) Dragged() *Element {
+	if !s.drag.active {
+		return nil
+	}
+	return s.drag.source
+}
+
+// DragTarget returns drop target under the dragged element or nil
+func (s *Scene) DragTarget() *Element {
+	return s.drag.target
+}
+
+// CancelDrag cancels current drag, DragLeave is invoked on target and rejected DragEnd on
+// source
+func (s *Scene) CancelDrag() {
+	d := &s.drag
+	if d.active {
+		s.enterTarget(nil)
+		d.source.Events.Invoke(DragEnd, &DragEvent{d.source, nil, d.payload, d.pos, true})
+		s.Redraw.Notify()
+	}
+	s.drag = drag{ghost: d.ghost}
+}
+
+// updateDrag starts, moves and finishes the dragging
+func (s *Scene) updateDrag(w input.Input, hit *Element, pos mat.Vec) {
+	d := &s.drag
+	if w.JustPressed(key.MouseLeft) {
+		s.CancelDrag()
+		for e := hit; e != nil; e = e.Parent {
+			if e.Draggable {
+				d.source, d.start = e, pos
+				break
+			}
+		}
+	}
+
+	if d.source == nil {
+		return
+	}
+
+	if d.source.Scene != s {
+		s.CancelDrag()
+		return
+	}
+
+	if !d.active {
+		if !w.Pressed(key.MouseLeft) {
+			d.source = nil
+			return
+		}
+		if d.start.To(pos).Len() < DragThreshold {
+			return
+		}
+
+		ev := &DragEvent{Source: d.source, Payload: d.source, Pos: pos}
+		d.source.Events.Invoke(DragStart, ev)
+		if ev.rejected {
+			d.source = nil
+			return
+		}
+		d.active, d.payload = true, ev.Payload
+		s.down[key.MouseLeft] = nil // dragging is not a click
+	}
+
+	d.pos = pos
+	s.Redraw.Notify() // ghost follows the mouse
+
+	var target *Element
+	for e := hit; e != nil; e = e.Parent {
+		if e.DropTarget && !d.source.isParentOf(e) {
+			target = e
+			break
+		}
+	}
+	s.enterTarget(target)
+
+	if !w.Pressed(key.MouseLeft) {
+		ev := &DragEvent{d.source, d.target, d.payload, pos, true}
+		if d.target != nil && d.accepted {
+			ev.rejected = false
+			d.target.Events.Invoke(Drop, ev)
+		} else {
+			s.enterTarget(nil)
+		}
+		d.source.Events.Invoke(DragEnd, ev)
+		s.drag = drag{ghost: d.ghost}
+	}
+}
+
+// enterTarget changes the drop target
+func (s *Scene) enterTarget(target *Element) {
+	d := &s.drag
+	if target == d.target {
+		return
+	}
+
+	if d.target != nil {
+		d.target.Events.Invoke(DragLeave, &DragEvent{d.source, d.target, d.payload, d.pos, false})
+	}
+
+	d.target, d.accepted = target, false
+	if target != nil {
+		ev := &DragEvent{d.source, target, d.payload, d.pos, false}
+		target.Events.Invoke(DragEnter, ev)
+		d.accepted = !ev.rejected
+	}
+}
+
+// drawGhost draws dragged element moved by the mouse
+func (s *Scene) drawGhost(t ggl.Target, canvas *drw.Geom) {
+	d := &s.drag
+	if !d.active {
+		return
+	}
+
+	d.ghost.Offset = d.pos.Sub(d.start)
+	d.ghost.Mask = s.GhostMask
+	d.source.redraw(&d.ghost, canvas)
+	d.ghost.Fetch(t)
+	d.ghost.Clear()
+}
+
+// ghost moves and masks triangles
+type ghost struct {
+	ggl.Data
+	Offset mat.Vec
+	Mask   mat.RGBA
+}
+
+// Accept implements ggl.Target interface
+func (g *ghost) Accept(vertexes ggl.Vertexes, indices ggl.Indices) {
+	ln := g.Vertexes.Len()
+	g.Data.Accept(vertexes, indices)
+	for i := ln; i < g.Vertexes.Len(); i++ {
+		v := &g.Vertexes[i]
+		v.Pos.AddE(g.Offset)
+		v.Color = v.Color.Mul(g.Mask)
+	}
+}
@@ -0,0 +1,244 @@
+package ui
+
+import (
+	"unicode"
+
+	"github.com/jakubDoka/gogen/str"
+	"github.com/jakubDoka/mlok/ggl/input"
+	"github.com/jakubDoka/mlok/ggl/key"
+	"github.com/jakubDoka/mlok/mat"
+)
+
+// editKind decides whether edits get merged into one undo step
+type editKind uint8
+
+const (
+	editNone editKind = iota
+	editTyping
+	editDeleting
+	editOther
+)
+
+// snapshot is state of Area stored in undo history
+type snapshot struct {
+	content    str.String
+	start, end int
+}
+
+// history is undo/redo stack of Area
+type history struct {
+	undo, redo []snapshot
+	last       editKind
+}
+
+// push stores state before edit, consecutive edits of same kind are merged into one step
+func (h *history) push(s snapshot, kind editKind, limit int) {
+	h.redo = h.redo[:0]
+	if kind == h.last && kind != editOther && len(h.undo) != 0 {
+		return
+	}
+	h.last = kind
+
+	h.undo = append(h.undo, s)
+	if limit > 0 && len(h.undo) > limit {
+		h.undo = append(h.undo[:0], h.undo[len(h.undo)-limit:]...)
+	}
+}
+
+// breakGroup makes next edit start new undo step
+func (h *history) breakGroup() {
+	h.last = editNone
+}
+
+// Undo reverts last edit, returns false if there is nothing to undo
+func (a *Area) Undo() bool {
+	return a.travel(&a.history.undo, &a.history.redo)
+}
+
+// Redo reverts last undo, returns false if there is nothing to redo
+func (a *Area) Redo() bool {
+	return a.travel(&a.history.redo, &a.history.undo)
+}
+
+// ClearHistory forgets all undo and redo steps
+func (a *Area) ClearHistory() {
+	a.history = history{}
+}
+
+// travel pops snapshot from one stack, pushes current state to other and applies it
+func (a *Area) travel(from, to *[]snapshot) bool {
+	if len(*from) == 0 {
+		return false
+	}
+
+	s := (*from)[len(*from)-1]
+	*from = (*from)[:len(*from)-1]
+	*to = append(*to, a.snapshot())
+	a.history.breakGroup()
+
+	a.Content = append(a.Content[:0], s.content...)
+	a.Text.Dirty()
+	a.Start, a.End = s.start, s.end
+	a.edited()
+	a.Events.Invoke(TextChanged, "")
+	return true
+}
+
+// snapshot copies current state
+func (a *Area) snapshot() snapshot {
+	return snapshot{append(str.String(nil), a.Content...), a.Start, a.End}
+}
+
+// SelectAll selects whole content
+func (a *Area) SelectAll() {
+	a.SetSelection(0, len(a.Content))
+}
+
+// SetSelection selects the range, end is where cursor is, indexes are clamped
+func (a *Area) SetSelection(start, end int) {
+	l := len(a.Content)
+	a.Start, a.End = mat.Maxi(mat.Mini(start, l), 0), mat.Maxi(mat.Mini(end, l), 0)
+	a.moved()
+}
+
+// Selection returns selected range, start is always smaller or equal to end
+func (a *Area) Selection() (start, end int) {
+	if a.Start > a.End {
+		return a.End, a.Start
+	}
+	return a.Start, a.End
+}
+
+// SelectedText returns selected part of content
+func (a *Area) SelectedText() string {
+	start, end := a.Selection()
+	return string(a.Content[start:end])
+}
+
+// SetText sets the content, cursor moves to the end and undo history is cleared
+func (a *Area) SetText(text string) {
+	a.Content = str.NString(text)
+	a.Text.Dirty()
+	a.Start, a.End = len(a.Content), len(a.Content)
+	a.ClearHistory()
+	a.dirty = true
+}
+
+// SetBindValue implements Bindable interface
+func (a *Area) SetBindValue(value interface{}) {
+	a.SetText(value.(string))
+}
+
+// replace replaces the range with text and records the change in history, change is
+// rejected if validation fails
+func (a *Area) replace(start, end int, text str.String, kind editKind) {
+	if err := a.validate(start, end, text); err != nil {
+		a.Events.Invoke(Invalid, err)
+		return
+	}
+
+	a.history.push(a.snapshot(), kind, a.UndoLimit)
+
+	a.Content.RemoveSlice(start, end)
+	a.Content.InsertSlice(start, text)
+	a.Text.Dirty()
+	a.Start, a.End = start+len(text), start+len(text)
+	a.edited()
+	a.Events.Invoke(TextChanged, string(text))
+}
+
+// moveCursor moves the cursor, if extend is true, selection anchor stays
+func (a *Area) moveCursor(to int, extend bool) {
+	a.End = mat.Maxi(mat.Mini(to, len(a.Content)), 0)
+	if !extend {
+		a.Start = a.End
+	}
+	a.moved()
+}
+
+// moved is called after the cursor moved
+func (a *Area) moved() {
+	a.history.breakGroup()
+	a.updateCursor()
+}
+
+// updateCursor updates line of the cursor and makes it visible
+func (a *Area) updateCursor() {
+	a.LineIdx, a.Line = a.UnprojectLine(a.End)
+	a.Blinker.Reset()
+	a.shown = true
+	a.Scene.Redraw.Notify()
+}
+
+// edited is called after content changed
+func (a *Area) edited() {
+	a.dirty = true
+	a.Blinker.Reset()
+	a.shown = true
+}
+
+// lineEnd returns index of the end of the cursor line
+func (a *Area) lineEnd() int {
+	if a.Line < 0 || a.Line >= a.Lines() {
+		return len(a.Content)
+	}
+	return a.ProjectLine(len(a.Content), a.Line)
+}
+
+// lineStart returns index of the start of the cursor line
+func (a *Area) lineStart() int {
+	if a.Line < 0 || a.Line >= a.Lines() {
+		return 0
+	}
+	return a.ProjectLine(0, a.Line)
+}
+
+// WordLeft returns index of start of the word before i
+func WordLeft(s str.String, i int) int {
+	for i > 0 && runeClass(s[i-1]) == 0 {
+		i--
+	}
+	if i == 0 {
+		return 0
+	}
+	c := runeClass(s[i-1])
+	for i > 0 && runeClass(s[i-1]) == c {
+		i--
+	}
+	return i
+}
+
+// WordRight returns index of start of the word after i
+func WordRight(s str.String, i int) int {
+	if i < len(s) {
+		c := runeClass(s[i])
+		for i < len(s) && c != 0 && runeClass(s[i]) == c {
+			i++
+		}
+	}
+	for i < len(s) && runeClass(s[i]) == 0 {
+		i++
+	}
+	return i
+}
+
+// runeClass returns 0 for spaces, 1 for word characters and 2 for the rest
+func runeClass(r rune) int {
+	switch {
+	case unicode.IsSpace(r):
+		return 0
+	case r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r):
+		return 1
+	}
+	return 2
+}
+
+// Ctrl returns whether any control key is pressed
+func Ctrl(w input.Input) bool {
+	return w.Pressed(key.LeftControl) || w.Pressed(key.RightControl)
+}
+
+// Shift returns whether any shift key is pressed
+func Shift(w input.Input) bool {
+	return w.Pressed(key.LeftShift) || w.Pressed(key.RightShift)
+}
@@ -7,6 +7,8 @@ import (
 
 	"github.com/jakubDoka/mlok/ggl"
 	"github.com/jakubDoka/mlok/ggl/drw"
+	"github.com/jakubDoka/mlok/ggl/input"
+	"github.com/jakubDoka/mlok/ggl/key"
 	"github.com/jakubDoka/mlok/logic/event"
 	"github.com/jakubDoka/mlok/mat"
 
@@ -40,11 +42,20 @@ var (
 //
 //	margin: 			aabb						// space that element must have around it self, can be fill*
 //	padding:            aabb                        // space inside element that must be empty
-//	size: 				vec							// size element must spam, can be fill*
-//	composition: 		vertical|horizontal			// composition of children (on top of each other or next to each other)
+//	size: 				vec							// size element must spam, can be fill* or percentage of parent space, see RawStyle.Size
+//	min_size/max_size:  vec                         // limits of size, padding is not included same as in size
+//	aspect_ratio:       float                       // height is computed from width as width / aspect_ratio
+//	composition: 		vertical|horizontal|wrap|horizontal_wrap|vertical_wrap|grid	// composition of children (on top of each other or next to each other), wrap compositions break children into rows or columns
+//	alignment:          start|center|end|stretch    // alignment of children inside a row or column of wrap composition
+//	gap:                vec                         // space between children and rows or columns of wrap and grid composition
+//	columns/rows:       tracks                      // tracks of grid composition, see RawStyle.Tracks
+//	cell:               vec                         // column and row of element in parents grid
+//	span:               vec                         // amount of columns and rows element spans in parents grid
 //	resize_mode/_x/_y: 	expand|shrink|exact|ignore	// how element will react to size of its children and parent
 //	relative:           bool                        // if property is true, element will ignore size of neighboring children
 //	offset:             vec                         // offset adds offset to element from its supposed position
+//	transition:         float [easing]              // animates style changes, showing and hiding, see RawStyle.Transition
+//	tab_index:          int                         // makes element focusable, focus moves in order of tab indexes
 //
 // Element also accepts some attributes:
 //
@@ -70,6 +81,14 @@ var (
 // hidden: hidden make element hidden from the start, you can write just hidden with no value, and it will be
 // considered true
 //
+// tab_index: same as tab_index style property, element with tab index can be focused by clicking on it or by
+// navigating with tab, shift+tab and arrow keys, see Scene.Focus
+//
+// bind: path to field of data bound by Scene.Bind, for example "Player.Name", value of field is displayed by
+// module and if module supports it, changes made by user are written back, see Bindable
+//
+// bind_hidden: path to bool field that controls whether element is hidden
+//
 // *fill = reminding space inside parent will be taken, if there is more children with fill prop, space is split equally
 //
 // Style behavior works very match like css, if you specify list of stiles they will be merged together, each overriding previous
@@ -93,9 +112,17 @@ type Element struct {
 
 	Styles              []string
 	relative, processed []*Element
+	lines               []wrapLine
+	tracks              [2][]float64
+	cell                [2]int
+	bindings            []*Binding
+	context             interface{}
+	collapse            *collapse
+	styled              bool
 
 	margin mat.AABB
 	size   mat.Vec
+	drawn  drawRange
 
 	children        Children
 	hidden, noName  bool
@@ -207,10 +234,11 @@ func (e *Element) LoadGoml(paths ...string) error {
 		if err != nil {
 			return err
 		}
-		err = e.AddGoml(bts)
+		elems, err := e.addGoml(bts)
 		if err != nil {
 			return err
 		}
+		e.Scene.addGomlFile(p, e, elems)
 	}
 
 	return nil
@@ -220,6 +248,12 @@ func (e *Element) LoadGoml(paths ...string) error {
 //
 // Panics if e.Scene == nil or e.Scene.Parser == nil.
 func (e *Element) AddGoml(source []byte) error {
+	_, err := e.addGoml(source)
+	return err
+}
+
+// addGoml adds elements parsed from goml and returns them
+func (e *Element) addGoml(source []byte) ([]*Element, error) {
 	if e.Scene == nil {
 		panic(errNoScene)
 	}
@@ -229,7 +263,7 @@ func (e *Element) AddGoml(source []byte) error {
 
 	elems, err := e.Scene.Parse(source)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	for _, ch := range elems {
@@ -239,7 +273,7 @@ func (e *Element) AddGoml(source []byte) error {
 		e.AddChild(ch.name, ch)
 	}
 
-	return nil
+	return elems, nil
 }
 
 // Listen registers event listener on element, and returns the listener.
@@ -382,13 +416,17 @@ func (e *Element) SetGroup(group string) {
 	e.group = group
 }
 
-// Hidden is hidden getter
+// Hidden is hidden getter, element that is being hidden with animation is considered hidden
 func (e *Element) Hidden() bool {
-	return e.hidden
+	return e.hidden || e.collapse != nil && e.collapse.hiding
 }
 
-// SetHidden is hidden setter
+// SetHidden is hidden setter, if element has transition, change is animated
 func (e *Element) SetHidden(value bool) {
+	if e.Scene != nil && e.Transition.Duration > 0 {
+		e.setHiddenAnimated(value)
+		return
+	}
 	e.hidden = value
 	e.onHiddenChange()
 }
@@ -424,6 +462,9 @@ func sumAABB(aabb mat.AABB) (sz mat.Vec) {
 func (e *Element) onHiddenChange() {
 	if e.Scene != nil {
 		e.Scene.Resize.Notify()
+		if e.hidden && e.Focused() {
+			e.Scene.Focus(nil)
+		}
 	}
 }
 
@@ -456,6 +497,12 @@ func (e *Element) onAdd(name string, index int, parent *Element) {
 }
 
 func (e *Element) onRemove() {
+	if e.Focused() {
+		e.Scene.Focus(nil)
+	}
+	if e.Scene != nil {
+		e.Scene.stopAnimations(e)
+	}
 	e.Parent = nil
 	e.Scene = nil
 }
@@ -480,16 +527,11 @@ func (e *Element) projectIndex(i *int) {
 	}
 }
 
-// update propagates update call on modules, it also invokes mouse event
-func (e *Element) update(p *Processor, w *ggl.Window, delta float64) {
-	// handle mouse exit, enter
-	contains := e.Frame.Contains(w.MousePos())
-	if e.Hovering && !contains {
-		e.Hovering = false
-		e.Events.Invoke(MouseExited, nil)
-	} else if !e.Hovering && contains {
-		e.Hovering = true
-		e.Events.Invoke(MouseEntered, nil)
+// update propagates update call on modules
+func (e *Element) update(p *Processor, w input.Input, delta float64) {
+	// deepest focusable element gets focus
+	if e.Hovering && e.Focusable() && w.JustPressed(key.MouseLeft) {
+		e.Scene.clicked = e
 	}
 
 	e.Module.Update(w, delta)
@@ -507,6 +549,12 @@ func (e *Element) redraw(t ggl.Target, canvas *drw.Geom) {
 		tar = e.Proc
 	}
 
+	// remember where element is so it can be redrawn in place
+	data, v, i := e.Scene.drawData(t)
+	if data != nil {
+		e.drawn = drawRange{e.Scene.drawGen, v + len(data.Vertexes), 0, i + len(data.Indices), 0}
+	}
+
 	canvas.Restart()
 	e.Module.Draw(tar, canvas)
 	e.forChild(IgnoreHidden, func(ch *Element) {
@@ -519,6 +567,10 @@ func (e *Element) redraw(t ggl.Target, canvas *drw.Geom) {
 		e.Proc.Fetch(t)
 		e.Proc.Clear()
 	}
+
+	if data != nil {
+		e.drawn.ve, e.drawn.ie = v+len(data.Vertexes), i+len(data.Indices)
+	}
 }
 
 // Init initializes element and its children
@@ -529,6 +581,9 @@ func (e *Element) init(s *Scene) {
 	if e.children.IsNil() {
 		e.children = NChildren()
 	}
+	if e.Events == nil { // root is not created by NElement
+		e.Events = event.String{}
+	}
 
 	e.Scene = s
 	s.InitStyle(e)
@@ -540,6 +595,7 @@ func (e *Element) init(s *Scene) {
 		ch[i].Value.init(s)
 	}
 	e.Module.PostInit()
+	s.addBindings(e)
 }
 
 // move moves element accordingly, assuming margin and size are resolved
@@ -547,8 +603,29 @@ func (e *Element) move(offset mat.Vec, horizontal bool) mat.Vec {
 	off := offset.Add(e.margin.Min).Add(e.Offest)
 	e.Frame = e.size.ToAABB().Moved(off)
 	off.AddE(e.Padding.Min)
-	oOff := off
 	e.Module.OnFrameChange()
+	if e.Composition == Grid {
+		e.moveGrid(off)
+	} else if e.Wraps() {
+		e.moveWrapped(off)
+	} else {
+		e.moveChildren(off)
+	}
+
+	if horizontal {
+		l, _, r, _ := e.margin.Deco()
+		offset.X += l + r + e.Frame.W()
+	} else {
+		_, b, _, t := e.margin.Deco()
+		offset.Y += b + t + e.Frame.H()
+	}
+
+	return offset
+}
+
+// moveChildren moves children in line
+func (e *Element) moveChildren(off mat.Vec) {
+	oOff := off
 	e.forChild(FCfg{
 		Filter:  IgnoreHidden.Filter,
 		Reverse: !e.Horizontal(),
@@ -560,16 +637,106 @@ func (e *Element) move(offset mat.Vec, horizontal bool) mat.Vec {
 		}
 
 	})
+}
 
-	if horizontal {
-		l, _, r, _ := e.margin.Deco()
-		offset.X += l + r + e.Frame.W()
+// moveWrapped moves children of wrap composition line by line, rows go from top to bottom
+// and columns from left to right, same applies for children inside the line
+func (e *Element) moveWrapped(off mat.Vec) {
+	e.forChild(IgnoreHidden, func(ch *Element) {
+		if ch.Relative {
+			ch.move(off, false)
+		}
+	})
+
+	top := off.Y + e.size.Y - fill.vSum(e.Padding)
+	if e.Horizontal() {
+		y := top
+		for i, l := range e.lines {
+			if i != 0 {
+				y -= e.Gap.Y
+			}
+			y -= l.cross
+			x := off.X
+			for j, ch := range e.processed[l.start:l.end] {
+				if j != 0 {
+					x += e.Gap.X
+				}
+				size := ch.size.Y + ch.margin.Min.Y + ch.margin.Max.Y
+				x = ch.move(mat.V(x, y+e.Alignment.offset(l.cross, size, true)), true).X
+			}
+		}
 	} else {
-		_, b, _, t := e.margin.Deco()
-		offset.Y += b + t + e.Frame.H()
+		x := off.X
+		for i, l := range e.lines {
+			if i != 0 {
+				x += e.Gap.X
+			}
+			y := top
+			for j, ch := range e.processed[l.start:l.end] {
+				if j != 0 {
+					y -= e.Gap.Y
+				}
+				y -= ch.size.Y + ch.margin.Min.Y + ch.margin.Max.Y
+				size := ch.size.X + ch.margin.Min.X + ch.margin.Max.X
+				ch.move(mat.V(x+e.Alignment.offset(l.cross, size, false), y), false)
+			}
+			x += l.cross
+		}
 	}
+}
 
-	return offset
+// moveGrid moves children of grid composition to their cells, rows go from top to bottom
+func (e *Element) moveGrid(off mat.Vec) {
+	e.forChild(IgnoreHidden, func(ch *Element) {
+		if ch.Relative {
+			ch.move(off, false)
+		}
+	})
+
+	// offset returns offset of track start
+	offset := func(tracks []float64, i int, gap float64) (o float64) {
+		for _, t := range tracks[:i] {
+			o += t + gap
+		}
+		return
+	}
+
+	top := off.Y + e.size.Y - fill.vSum(e.Padding)
+	for _, ch := range e.processed {
+		x := off.X + offset(e.tracks[X], ch.cell[X], e.Gap.X)
+		y := top - offset(e.tracks[Y], ch.cell[Y], e.Gap.Y) - ch.size.Y - ch.margin.Min.Y - ch.margin.Max.Y
+		ch.move(mat.V(x, y), false)
+	}
+}
+
+// place resolves grid cells of children, children with specified cell are placed first
+// and rest fills free cells in row-major order
+func (e *Element) place() {
+	columns := mat.Maxi(len(e.Columns), 1)
+	occupied := map[[2]int]bool{}
+	for _, ch := range e.processed {
+		if ch.Cell[X] < 0 || ch.Cell[Y] < 0 {
+			continue
+		}
+		ch.cell = ch.Cell
+		for x := 0; x < ch.Span[X]; x++ {
+			for y := 0; y < ch.Span[Y]; y++ {
+				occupied[[2]int{ch.cell[X] + x, ch.cell[Y] + y}] = true
+			}
+		}
+	}
+
+	cursor := 0
+	for _, ch := range e.processed {
+		if ch.Cell[X] >= 0 && ch.Cell[Y] >= 0 {
+			continue
+		}
+		for occupied[[2]int{cursor % columns, cursor / columns}] {
+			cursor++
+		}
+		ch.cell = [2]int{cursor % columns, cursor / columns}
+		cursor++
+	}
 }
 
 // FCfg is configuration for Element.forChild method
@@ -597,12 +764,26 @@ var (
 const (
 	MouseEntered = "mouse_entered"
 	MouseExited  = "mouse_exited"
+	MouseDown    = "mouse_down"
+	MouseUp      = "mouse_up"
+	MouseClick   = "mouse_click"
+	MouseWheel   = "mouse_wheel"
 	Click        = "click"
 	Deselect     = "deselect"
 	Select       = "select"
 	TextChanged  = "text_changed"
+	Invalid      = "invalid"
 	Error        = "error"
 	Enter        = "enter"
+	Focus        = "focus"
+	Blur         = "blur"
+	Changed      = "changed"
+	Dismissed    = "dismissed"
+	DragStart    = "drag_start"
+	DragEnter    = "drag_enter"
+	DragLeave    = "drag_leave"
+	Drop         = "drop"
+	DragEnd      = "drag_end"
 )
 
 // InputState ...
@@ -626,7 +807,7 @@ type Module interface {
 	// DrawOnTop does the same thing as draw, but on top of children
 	DrawOnTop(ggl.Target, *drw.Geom)
 	// Update is stage where your event handling and visual updates should happen
-	Update(*ggl.Window, float64)
+	Update(input.Input, float64)
 	// OnFrameChange is called by processor when frame of element changes
 	OnFrameChange()
 
@@ -657,8 +838,12 @@ func (m *ModuleBase) DefaultStyle() goss.Style {
 
 // Init implements Module interface
 func (m *ModuleBase) Init(div *Element) {
+	old, reinit := m.Background, m.Element != nil
 	m.Element = div
 	m.Background = m.RGBA("background", mat.Transparent)
+	if reinit {
+		m.transitionBackground(old)
+	}
 }
 
 func (m *ModuleBase) PostInit() {}
@@ -674,7 +859,7 @@ func (m *ModuleBase) DrawOnTop(t ggl.Target, g *drw.Geom) {
 }
 
 // Update implements Module interface
-func (*ModuleBase) Update(*ggl.Window, float64) {}
+func (*ModuleBase) Update(input.Input, float64) {}
 
 // OnFrameChange implements Module interface
 func (*ModuleBase) OnFrameChange() {}
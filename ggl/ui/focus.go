@@ -0,0 +1,206 @@
+package ui
+
+import (
+	"math"
+	"sort"
+
+	"github.com/jakubDoka/mlok/ggl/input"
+	"github.com/jakubDoka/mlok/ggl/key"
+	"github.com/jakubDoka/mlok/mat"
+)
+
+// FocusHandler can be implemented by Module to get notified when its element gains or
+// loses focus, methods are called before Focus and Blur events are invoked
+type FocusHandler interface {
+	OnFocus()
+	OnBlur()
+}
+
+// KeyCapturer can be implemented by Module that uses keys scene uses for navigation,
+// if Captures returns true for a key, scene will not move focus on its press
+type KeyCapturer interface {
+	Captures(k key.Key) bool
+}
+
+// Direction is key that moves focus and direction in witch focus moves
+type Direction struct {
+	Key key.Key
+	Dir mat.Vec
+}
+
+// Directions are keys that move the focus, they are processed in order so result is same
+// when multiple keys are pressed at once
+var Directions = []Direction{
+	{key.Left, mat.V(-1, 0)},
+	{key.Right, mat.V(1, 0)},
+	{key.Up, mat.V(0, 1)},
+	{key.Down, mat.V(0, -1)},
+}
+
+// Focused returns currently focused element or nil
+func (s *Scene) Focused() *Element {
+	return s.focused
+}
+
+// Focus moves focus to given element, passing nil just blurs currently focused element.
+// Blur event is invoked on previously focused element and Focus event on the new one.
+func (s *Scene) Focus(e *Element) {
+	if s.focused == e {
+		return
+	}
+
+	if old := s.focused; old != nil {
+		s.focused = nil
+		if f, ok := old.Module.(FocusHandler); ok {
+			f.OnBlur()
+		}
+		old.Events.Invoke(Blur, nil)
+	}
+
+	s.focused = e
+	if e != nil {
+		if f, ok := e.Module.(FocusHandler); ok {
+			f.OnFocus()
+		}
+		e.Events.Invoke(Focus, nil)
+	}
+
+	s.Redraw.Notify()
+}
+
+// FocusNext moves focus to next element in tab order, or previous one if reverse
+// is true. Focus wraps around when it reaches the end.
+func (s *Scene) FocusNext(reverse bool) {
+	order := s.TabOrder()
+	if len(order) == 0 {
+		return
+	}
+
+	idx := -1
+	for i, e := range order {
+		if e == s.focused {
+			idx = i
+			break
+		}
+	}
+
+	if reverse {
+		if idx <= 0 {
+			idx = len(order)
+		}
+		idx--
+	} else {
+		idx = (idx + 1) % len(order)
+	}
+
+	s.Focus(order[idx])
+}
+
+// FocusDirection moves focus to closest focusable element in given direction, distance
+// perpendicular to direction counts twice. If nothing is focused, nothing happens so arrow
+// keys used by game do not steal the focus, use FocusNext to focus first element.
+func (s *Scene) FocusDirection(dir mat.Vec) {
+	if s.focused == nil {
+		return
+	}
+	order := s.TabOrder()
+
+	var (
+		center = s.focused.Frame.Center()
+		best   *Element
+		score  = math.MaxFloat64
+	)
+
+	for _, e := range order {
+		if e == s.focused {
+			continue
+		}
+
+		delta := center.To(e.Frame.Center())
+		along := delta.Dot(dir)
+		if along <= 0 {
+			continue
+		}
+
+		if sc := along + 2*math.Abs(delta.Dot(dir.Normal())); sc < score {
+			best, score = e, sc
+		}
+	}
+
+	if best != nil {
+		s.Focus(best)
+	}
+}
+
+// TabOrder returns all visible focusable elements sorted by their tab index, elements
+// with equal index keep the order of the tree, elements of layers blocked by modal are
+// excluded
+func (s *Scene) TabOrder() []*Element {
+	s.tabOrder = s.tabOrder[:0]
+	for l := s.ModalLayer(); l < layerCount; l++ {
+		s.Layer(l).collectFocusable(&s.tabOrder)
+	}
+	sort.SliceStable(s.tabOrder, func(i, j int) bool {
+		return s.tabOrder[i].TabIndex < s.tabOrder[j].TabIndex
+	})
+	return s.tabOrder
+}
+
+// updateFocus handles focus changes caused by input
+func (s *Scene) updateFocus(w input.Input) {
+	if s.focused != nil {
+		if l, ok := s.LayerOf(s.focused); ok && l < s.ModalLayer() {
+			s.Focus(nil)
+		}
+	}
+
+	if w.JustPressed(key.MouseLeft) {
+		s.Focus(s.clicked)
+	}
+	s.clicked = nil
+
+	if s.pressed(w, key.Tab) {
+		s.FocusNext(w.Pressed(key.LeftShift) || w.Pressed(key.RightShift))
+	}
+
+	for _, d := range Directions {
+		if s.pressed(w, d.Key) {
+			s.FocusDirection(d.Dir)
+		}
+	}
+}
+
+// pressed reports whether navigation key was pressed and focused element does not capture it
+func (s *Scene) pressed(w input.Input, k key.Key) bool {
+	if !w.JustPressed(k) && !w.Repeated(k) {
+		return false
+	}
+
+	if s.focused != nil {
+		if c, ok := s.focused.Module.(KeyCapturer); ok && c.Captures(k) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Focusable reports whether element can receive focus
+func (e *Element) Focusable() bool {
+	return e.TabIndex >= 0
+}
+
+// Focused reports whether element is focused
+func (e *Element) Focused() bool {
+	return e.Scene != nil && e.Scene.focused == e
+}
+
+// collectFocusable appends all visible focusable elements in tree order
+func (e *Element) collectFocusable(buff *[]*Element) {
+	if e.Focusable() {
+		*buff = append(*buff, e)
+	}
+	e.forChild(IgnoreHidden, func(ch *Element) {
+		ch.collectFocusable(buff)
+	})
+}
@@ -0,0 +1,144 @@
+package ui
+
+import (
+	"github.com/jakubDoka/mlok/ggl/input"
+	"github.com/jakubDoka/mlok/ggl/key"
+	"github.com/jakubDoka/mlok/mat"
+)
+
+// Layer is z-ordered root of the scene, layers are drawn in order of declaration so later
+// layers are on top of earlier ones and they also get the mouse first
+//
+//	err := scene.Layer(ui.PopupLayer).LoadGoml("popup.goml")
+//
+// Direct children of layers can have following style:
+//
+//	modal:			bool	// while element is visible, layers below do not receive any input
+//	dismissible:	bool	// element is hidden when user clicks outside of it and Dismissed
+//							// event is invoked on it
+//
+// Any element can have tooltip attribute, its text is shown on TooltipLayer after mouse
+// stays on element for Scene.TooltipDelay. Tooltip is Text element with "tooltip" style so
+// you can customize it from goss.
+type Layer uint8
+
+// Layer enum
+const (
+	// BaseLayer is Scene.Root
+	BaseLayer Layer = iota
+	PopupLayer
+	TooltipLayer
+	OverlayLayer
+
+	layerCount
+)
+
+// default tooltip settings
+var (
+	DefaultTooltipDelay = .5
+	// TooltipOffset is offset of tooltip from mouse, tooltip is above the mouse by default
+	TooltipOffset = mat.V(8, 8)
+)
+
+// noInput is passed to elements of layers blocked by modal
+var noInput input.Frames
+
+// Layer returns root element of layer
+func (s *Scene) Layer(l Layer) *Element {
+	if l == BaseLayer {
+		return &s.Root
+	}
+	return &s.layers[l-1]
+}
+
+// LayerOf returns layer element is in, ok is false if element is not part of scene
+func (s *Scene) LayerOf(e *Element) (l Layer, ok bool) {
+	for e.Parent != nil {
+		e = e.Parent
+	}
+	for l = BaseLayer; l < layerCount; l++ {
+		if s.Layer(l) == e {
+			return l, true
+		}
+	}
+	return
+}
+
+// ModalLayer returns the top layer with visible modal element, layers below it do not
+// receive input, BaseLayer is returned if there is no modal
+func (s *Scene) ModalLayer() Layer {
+	for l := layerCount - 1; l > BaseLayer; l-- {
+		modal := false
+		s.Layer(l).forChild(IgnoreHidden, func(ch *Element) {
+			modal = modal || ch.Modal
+		})
+		if modal {
+			return l
+		}
+	}
+	return BaseLayer
+}
+
+// Tooltip returns element that displays tooltips
+func (s *Scene) Tooltip() *Element {
+	if s.tooltip == nil {
+		s.tooltip = NElement()
+		s.tooltip.Module = &Text{}
+		s.tooltip.Styles = []string{"tooltip"}
+		s.tooltip.Raw.Style = map[string][]interface{}{"relative": {true}}
+		s.tooltip.hidden = true
+		s.Layer(TooltipLayer).AddChild("tooltip", s.tooltip)
+	}
+	return s.tooltip
+}
+
+// forLayers calls fn on all layers from the bottom
+func (s *Scene) forLayers(fn func(l *Element)) {
+	for l := BaseLayer; l < layerCount; l++ {
+		fn(s.Layer(l))
+	}
+}
+
+// dismiss hides dismissible elements that do not contain hit
+func (s *Scene) dismiss(hit *Element) {
+	modal := int(s.ModalLayer())
+	for l := int(layerCount) - 1; l >= modal; l-- {
+		s.Layer(Layer(l)).forChild(IgnoreHidden, func(ch *Element) {
+			if ch.Dismissible && !ch.isParentOf(hit) {
+				ch.SetHidden(true)
+				ch.Events.Invoke(Dismissed, nil)
+			}
+		})
+	}
+}
+
+// updateTooltip shows tooltip of hovered element after delay
+func (s *Scene) updateTooltip(w input.Input, delta float64) {
+	var owner *Element
+	for _, e := range s.hovered {
+		if _, ok := e.Raw.Attributes["tooltip"]; ok {
+			owner = e
+			break
+		}
+	}
+
+	if owner != s.tipOwner || w.JustPressed(key.MouseLeft) {
+		s.tipOwner, s.tipTime = owner, 0
+		if s.tooltip != nil && !s.tooltip.hidden {
+			s.tooltip.SetHidden(true)
+		}
+		return
+	}
+
+	if owner == nil || s.tipTime >= s.TooltipDelay {
+		return
+	}
+
+	s.tipTime += delta
+	if s.tipTime >= s.TooltipDelay {
+		tip := s.Tooltip()
+		tip.Module.(*Text).SetText(owner.Raw.Attributes.Ident("tooltip", ""))
+		tip.Offest = w.MousePos().Sub(s.Layer(TooltipLayer).Frame.Min).Add(TooltipOffset)
+		tip.SetHidden(false)
+	}
+}
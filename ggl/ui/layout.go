@@ -0,0 +1,105 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jakubDoka/mlok/mat"
+)
+
+// Layout computes frames of all elements in the scene as if Processor with given frame
+// was resizing it, nothing is updated or drawn so it can run without window:
+//
+//	s := ui.NEmptyScene()
+//	s.Parser = ui.NParser()
+//	err := s.Root.AddGoml(source)
+//	...
+//	s.Layout(mat.A(0, 0, 800, 600))
+//	fmt.Println(s.Root.Dump())
+func (s *Scene) Layout(frame mat.AABB) {
+	var p Processor
+	p.SetScene(s)
+	p.SetFrame(frame)
+	p.Resize()
+}
+
+// LayoutDump is computed layout of element and its visible children, it can be
+// printed as tree or encoded as json
+type LayoutDump struct {
+	Path   string `json:"path"`
+	Module string `json:"module"`
+	// Frame is space element occupies, Margin is resolved margin around the Frame and
+	// Padding is space inside the Frame
+	Frame    mat.AABB     `json:"frame"`
+	Margin   mat.AABB     `json:"margin"`
+	Padding  mat.AABB     `json:"padding"`
+	Children []LayoutDump `json:"children,omitempty"`
+}
+
+// Dump returns layout of element and its visible children, layout is valid only after
+// scene was resized
+func (e *Element) Dump() LayoutDump {
+	d := LayoutDump{
+		Path:    e.Path(),
+		Module:  e.Raw.Name,
+		Frame:   e.Frame,
+		Margin:  e.margin,
+		Padding: e.Padding,
+	}
+	if d.Module == "" {
+		d.Module = fmt.Sprintf("%T", e.Module)
+	}
+
+	e.forChild(IgnoreHidden, func(ch *Element) {
+		d.Children = append(d.Children, ch.Dump())
+	})
+
+	return d
+}
+
+// Find returns dump of element under the path relative to d, path has same format as in
+// Element.Child
+func (d LayoutDump) Find(path string) (LayoutDump, bool) {
+	prefix := d.Path + "."
+o:
+	for _, name := range strings.Split(path, ".") {
+		prefix += name
+		for _, ch := range d.Children {
+			if ch.Path == prefix {
+				d = ch
+				prefix += "."
+				continue o
+			}
+		}
+		return d, false
+	}
+	return d, true
+}
+
+// String returns dump as indented tree, each line contains name, module, frame, and
+// margin and padding if they are not zero
+func (d LayoutDump) String() string {
+	var sb strings.Builder
+	d.write(&sb, 0)
+	return sb.String()
+}
+
+func (d LayoutDump) write(sb *strings.Builder, depth int) {
+	name := d.Path
+	if i := strings.LastIndexByte(name, '.'); i != -1 {
+		name = name[i+1:]
+	}
+
+	fmt.Fprintf(sb, "%s%s %s %v", strings.Repeat("\t", depth), name, d.Module, d.Frame)
+	if d.Margin != mat.ZA {
+		fmt.Fprintf(sb, " margin %v", d.Margin)
+	}
+	if d.Padding != mat.ZA {
+		fmt.Fprintf(sb, " padding %v", d.Padding)
+	}
+	sb.WriteByte('\n')
+
+	for _, ch := range d.Children {
+		ch.write(sb, depth+1)
+	}
+}
@@ -0,0 +1,281 @@
+package ui
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+
+	"github.com/jakubDoka/goml"
+	"github.com/jakubDoka/goml/goss"
+	"github.com/jakubDoka/mlok/ggl/input"
+	"github.com/jakubDoka/sterr"
+)
+
+// List related errors
+var (
+	ErrNoTemplate = sterr.New("list has no template, first child of list is used as template")
+	ErrNoParser   = sterr.New("list needs scene with parser to instantiate template")
+)
+
+// ListSource provides items for List
+type ListSource interface {
+	Len() int
+	Item(i int) interface{}
+}
+
+// List displays items from ListSource, each item gets its own row instantiated from template
+// which is the first child of list. Item is set as row context (see Element.SetContext) so
+// row children can bind to item fields. Rows are created only for visible items, visible
+// area is frame of closest Scroll ancestor or whole list if there is none. When rows go
+// out of view they are reused for items that came to view so scrolling trough thousands of
+// items does not create any elements.
+//
+// List is always vertical, all rows should have same height and list takes height of all
+// rows together. Amount of items is checked each update, if items change without changing
+// the length, call Refresh.
+//
+// style:
+//
+//	item_size:	float	// height of row, if 0, height of first row is used
+//
+//	<scroll style="bar_y: true;">
+//		<list id="players" style="size: fill 0;">
+//			<div><text bind="Name"/><text bind="Score"/></div>
+//		</list>
+//	</scroll>
+//	...
+//	scene.ID("players").Module.(*ui.List).SetSlice(&players)
+type List struct {
+	ModuleBase
+
+	Template goml.Element
+	Source   ListSource
+	ItemSize float64
+
+	rows            []*Element
+	first, last, ln int
+}
+
+// New implements ModuleFactory interface
+func (l *List) New() Module {
+	return &List{}
+}
+
+// Init implements Module interface
+func (l *List) Init(e *Element) {
+	l.ModuleBase.Init(e)
+	l.ItemSize = e.Float("item_size", 0)
+}
+
+// PostInit implements Module interface
+func (l *List) PostInit() {
+	if l.Template.Name == "" {
+		if l.ChildCount() == 0 {
+			l.Scene.Log(l.Element, ErrNoTemplate)
+			return
+		}
+		tmp := l.ChildAt(0)
+		l.Template = tmp.Raw
+		l.RemoveChild(tmp.Name())
+	}
+
+	l.Refresh()
+}
+
+// SetSource sets the source of items and refreshes the list
+func (l *List) SetSource(source ListSource) {
+	l.Source = source
+	l.Refresh()
+}
+
+// SetSlice sets slice as source of items, it has to be a pointer to slice if you want list
+// to notice appended items. Items that are addressable are passed to rows as pointers so
+// rows can edit them.
+func (l *List) SetSlice(slice interface{}) {
+	l.SetSource(SliceSource{reflect.ValueOf(slice)})
+}
+
+// Refresh makes all rows pull the data again
+func (l *List) Refresh() {
+	l.first, l.last = 0, 0
+	if l.Element != nil {
+		l.Relayout()
+	}
+}
+
+// Len returns amount of items
+func (l *List) Len() int {
+	if l.Source == nil {
+		return 0
+	}
+	return l.Source.Len()
+}
+
+// Row returns row displaying item with given index, or nil if item is not visible
+func (l *List) Row(item int) *Element {
+	if item < l.first || item >= l.last {
+		return nil
+	}
+	return l.rows[item-l.first]
+}
+
+// Update implements Module interface
+func (l *List) Update(w input.Input, delta float64) {
+	if ln := l.Len(); ln != l.ln {
+		l.ln = ln
+		l.Refresh()
+	}
+}
+
+// Height implements Module interface
+func (l *List) Height(takable, taken float64) float64 {
+	l.ln = l.Len()
+	return l.itemSize() * float64(l.ln)
+}
+
+// OnFrameChange implements Module interface
+func (l *List) OnFrameChange() {
+	size := l.itemSize()
+	if size == 0 {
+		if len(l.rows) == 0 {
+			// we have to create the first row to find out the size
+			l.show(0, 1)
+		}
+		return
+	}
+
+	view := l.Frame
+	for p := l.Parent; p != nil; p = p.Parent {
+		if _, ok := p.Module.(*Scroll); ok {
+			view = p.Frame
+			break
+		}
+	}
+
+	top := l.Frame.Max.Y - l.Padding.Max.Y
+	l.show(int(math.Floor((top-view.Max.Y)/size)), int(math.Ceil((top-view.Min.Y)/size)))
+
+	top -= l.Frame.Min.Y + l.Padding.Min.Y
+	for i, r := range l.rows[:l.last-l.first] {
+		r.Offest.Y = top - float64(l.first+i+1)*size
+	}
+}
+
+// show makes rows display items in given range, range is clamped, rows that already
+// display item from the range are kept
+func (l *List) show(first, last int) {
+	first = int(math.Max(float64(first), 0))
+	last = int(math.Min(float64(last), float64(l.Len())))
+	if last < first {
+		last = first
+	}
+
+	if first == l.first && last == l.last {
+		return
+	}
+
+	for len(l.rows) < last-first {
+		if !l.addRow() {
+			last = first + len(l.rows)
+			break
+		}
+	}
+
+	rows := make([]*Element, len(l.rows))
+	used := make([]bool, len(l.rows))
+	for i := first; i < last; i++ {
+		if i >= l.first && i < l.last {
+			rows[i-first] = l.rows[i-l.first]
+			used[i-l.first] = true
+		}
+	}
+
+	j := 0
+	for i := range rows {
+		if rows[i] != nil {
+			continue
+		}
+		for used[j] {
+			j++
+		}
+		rows[i] = l.rows[j]
+		j++
+
+		if idx := first + i; idx < last {
+			rows[i].SetContext(l.Source.Item(idx))
+			if rows[i].hidden {
+				rows[i].SetHidden(false)
+			}
+		} else if !rows[i].hidden {
+			rows[i].SetHidden(true)
+		}
+	}
+
+	l.rows = rows
+	l.first, l.last = first, last
+	// rows changed during layout, processor lays them out again before drawing
+	l.Relayout()
+}
+
+// addRow instantiates template, returns false if it failed
+func (l *List) addRow() bool {
+	if l.Template.Name == "" {
+		return false
+	}
+	if l.Scene.Parser == nil {
+		l.Scene.Log(l.Element, ErrNoParser)
+		return false
+	}
+
+	row, err := l.Scene.Parser.translateElement(len(l.rows), l.Template)
+	if err != nil {
+		l.Scene.Log(l.Element, err)
+		return false
+	}
+	if row.Raw.Style == nil {
+		row.Raw.Style = goss.Style{}
+	}
+	row.Raw.Style["relative"] = []interface{}{true}
+	row.hidden = true
+
+	l.rows = append(l.rows, row)
+	l.AddChild(strconv.Itoa(len(l.rows)), row)
+
+	return true
+}
+
+// itemSize returns ItemSize or size of first row if ItemSize is 0
+func (l *List) itemSize() float64 {
+	if l.ItemSize != 0 || len(l.rows) == 0 {
+		return l.ItemSize
+	}
+	r := l.rows[0]
+	return r.size.Y + sumAABB(r.margin).Y
+}
+
+// SliceSource is ListSource that uses reflection to access slice
+type SliceSource struct {
+	Value reflect.Value
+}
+
+// Len implements ListSource interface
+func (s SliceSource) Len() int {
+	v := s.Value
+	for v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	return v.Len()
+}
+
+// Item implements ListSource interface, if item is addressable, pointer to it is returned
+func (s SliceSource) Item(i int) interface{} {
+	v := s.Value
+	for v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	item := v.Index(i)
+	if item.CanAddr() {
+		return item.Addr().Interface()
+	}
+	return item.Interface()
+}
@@ -0,0 +1,230 @@
+package ui
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jakubDoka/mlok/ggl/txt"
+	"github.com/jakubDoka/mlok/load"
+	"github.com/jakubDoka/mlok/mat"
+
+	"github.com/jakubDoka/goml/goss"
+)
+
+// SetScale sets ui scale factor, sizes from styles, text scale and patch scale are multiplied
+// by it, styles are reloaded when scale changes. Zero resets scale to 1.
+func (p *Processor) SetScale(scale float64) {
+	p.scale, p.scaleBase = scale, mat.ZV
+	p.updateMedia()
+}
+
+// SetScaleBase makes scale derived from the frame, base is resolution ui was designed for and
+// scale is chosen so that base fits into the frame
+//
+//	p.SetScaleBase(mat.V(1280, 720)) // scale is 3 on 4K window
+func (p *Processor) SetScaleBase(base mat.Vec) {
+	p.scaleBase = base
+	p.updateMedia()
+}
+
+// Scale returns current ui scale factor
+func (p *Processor) Scale() float64 {
+	if p.scaleBase.X > 0 && p.scaleBase.Y > 0 && p.frame.W() > 0 && p.frame.H() > 0 {
+		return math.Min(p.frame.W()/p.scaleBase.X, p.frame.H()/p.scaleBase.Y)
+	}
+	if p.scale > 0 {
+		return p.scale
+	}
+	return 1
+}
+
+// updateMedia passes frame and scale to scene
+func (p *Processor) updateMedia() {
+	if p.scene != nil {
+		p.scene.setMedia(p.frame.Size(), p.Scale())
+	}
+}
+
+// Scale returns ui scale factor of processor that processes the scene
+func (s *Scene) Scale() float64 {
+	return s.scale
+}
+
+// Markdown returns markdown from assets that fits the ui scale best and scale it was made
+// for. Markdowns for bigger scales can be registered as name@scale, markdown with smallest
+// scale that is not smaller then ui scale is picked so text is not blurry:
+//
+//	assets.Markdowns["default@2"] = markdownWithDoubleFontSize
+func (s *Scene) Markdown(name string) (m *txt.Markdown, scale float64) {
+	m, ok := s.Assets.Markdowns[name]
+	if ok {
+		scale = 1
+	}
+
+	prefix := name + "@"
+	for k, v := range s.Assets.Markdowns {
+		if !strings.HasPrefix(k, prefix) {
+			continue
+		}
+		sc, err := strconv.ParseFloat(k[len(prefix):], 64)
+		if err != nil || sc <= 0 {
+			continue
+		}
+		// sc fits better if it is closer from above or current one is too small
+		if m == nil || scale < s.scale && sc > scale || sc >= s.scale && sc < scale {
+			m, scale = v, sc
+		}
+	}
+
+	return
+}
+
+// mediaRule is goss style that is applied on top of target style while its conditions hold
+type mediaRule struct {
+	name, target string
+	style        goss.Style
+	conds        []mediaCond
+	active       bool
+}
+
+// mediaCond is media feature and its limit
+type mediaCond struct {
+	feature string
+	value   float64
+}
+
+// MediaFeatures are features that media rules can use, width and height are size of
+// processor frame divided by ui scale
+var MediaFeatures = map[string]func(size mat.Vec, scale, value float64) bool{
+	"min_width":  func(size mat.Vec, scale, value float64) bool { return size.X >= value },
+	"max_width":  func(size mat.Vec, scale, value float64) bool { return size.X <= value },
+	"min_height": func(size mat.Vec, scale, value float64) bool { return size.Y >= value },
+	"max_height": func(size mat.Vec, scale, value float64) bool { return size.Y <= value },
+	"min_scale":  func(size mat.Vec, scale, value float64) bool { return scale >= value },
+	"max_scale":  func(size mat.Vec, scale, value float64) bool { return scale <= value },
+}
+
+// setMedia updates media rules and reloads styles if some rule or scale changed
+func (s *Scene) setMedia(size mat.Vec, scale float64) {
+	reload := scale != s.scale
+	s.scale, s.media = scale, size.Divided(scale)
+	if s.updateRules() || reload {
+		s.forLayers(s.ReloadStyle)
+	}
+}
+
+// updateRules evaluates media rules and returns whether some rule changed, rules are
+// collected from assets again if they were invalidated.
+//
+// Style with media property is a rule, it is applied on top of style named by target when
+// all features in media hold. Features are listed in MediaFeatures. Rules are applied
+// in order of names and inline style still overrides them:
+//
+//	menu{ size: 400 fill; }
+//	menu_compact{
+//		media: max_width 800;
+//		target: menu;
+//		size: 200 fill;
+//	}
+func (s *Scene) updateRules() (changed bool) {
+	if !s.rulesValid {
+		s.rulesValid = true
+		s.rules = s.rules[:0]
+		for name, st := range s.Assets.Styles {
+			if r, ok := parseRule(name, st); ok {
+				s.rules = append(s.rules, r)
+			}
+		}
+		sort.Slice(s.rules, func(i, j int) bool { return s.rules[i].name < s.rules[j].name })
+		changed = len(s.rules) != 0
+	}
+
+	for i := range s.rules {
+		r := &s.rules[i]
+		active := true
+		for _, c := range r.conds {
+			active = active && MediaFeatures[c.feature](s.media, s.scale, c.value)
+		}
+		changed = changed || active != r.active
+		r.active = active
+	}
+
+	return
+}
+
+// parseRule parses media rule, ok is false if style is not a rule or it is invalid
+func parseRule(name string, st goss.Style) (r mediaRule, ok bool) {
+	media, ok := st["media"]
+	if !ok || len(media)%2 != 0 {
+		return r, false
+	}
+
+	r.name, r.style = name, st
+	r.target = load.RawStyle{Style: st}.Ident("target", "")
+	if r.target == "" {
+		return r, false
+	}
+
+	var value [1]float64
+	for i := 0; i < len(media); i += 2 {
+		feature, ok := media[i].(string)
+		if _, known := MediaFeatures[feature]; !ok || !known || load.CollectFloats(media[i+1:i+2], value[:]) != 1 {
+			return r, false
+		}
+		r.conds = append(r.conds, mediaCond{feature, value[0]})
+	}
+
+	return r, true
+}
+
+// applyRules overwrites style by active media rules targeting name
+func (s *Scene) applyRules(name string, style goss.Style) {
+	if !s.rulesValid {
+		s.updateRules()
+	}
+	for _, r := range s.rules {
+		if r.active && r.target == name {
+			r.style.Overwrite(style)
+		}
+	}
+}
+
+// scaled returns value multiplied by ui scale, Fill and unknown stay as they are
+func (s *Props) scaled(v float64) float64 {
+	if v == Fill || v == unknown {
+		return v
+	}
+	return v * s.scale
+}
+
+// scaledVec returns vector under the key multiplied by ui scale, def is returned as is
+// if key is not present
+func (s *Props) scaledVec(key string, def mat.Vec) mat.Vec {
+	if _, ok := s.Style[key]; !ok {
+		return def
+	}
+	v := s.Vec(key, def)
+	return mat.V(s.scaled(v.X), s.scaled(v.Y))
+}
+
+// scaledTracks returns tracks under the key with fixed sizes multiplied by ui scale
+func (s *Props) scaledTracks(key string) []Track {
+	t := s.Tracks(key)
+	for i := range t {
+		if t[i].Mode == TrackPixels {
+			t[i].Value = s.scaled(t[i].Value)
+		}
+	}
+	return t
+}
+
+// scaledAABB is like scaledVec but for AABB
+func (s *Props) scaledAABB(key string, def mat.AABB) mat.AABB {
+	if _, ok := s.Style[key]; !ok {
+		return def
+	}
+	a := s.AABB(key, def)
+	return mat.A(s.scaled(a.Min.X), s.scaled(a.Min.Y), s.scaled(a.Max.X), s.scaled(a.Max.Y))
+}
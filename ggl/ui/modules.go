@@ -5,6 +5,7 @@ import (
 
 	"github.com/jakubDoka/mlok/ggl"
 	"github.com/jakubDoka/mlok/ggl/drw"
+	"github.com/jakubDoka/mlok/ggl/input"
 	"github.com/jakubDoka/mlok/ggl/key"
 	"github.com/jakubDoka/mlok/ggl/txt"
 	"github.com/jakubDoka/mlok/logic/timer"
@@ -26,15 +27,41 @@ import (
 //	auto_frequency:			float		// when you hold some button that controls the input, action starts
 //										// repeating and this sets how often it repeats
 //	hold_responce_speed:	float		// how long you have to hold on to button until it starts repeating
+//	tab_input:				bool		// tab is written into area instead of moving the focus
+//	undo_limit:				int			// how many undo steps are remembered, 0 means no limit
+//	max_length:				int			// maximal amount of characters, 0 means no limit
+//	numeric:				int|float	// only numbers are accepted
+//	min_value:				float		// minimal value of number if numeric is set
+//	max_value:				float		// maximal value of number if numeric is set
+//	pattern:				custom_type // regex taken by name from assets, whole content has to match
+//	validator:				custom_type // validator taken by name from assets
+//	password:				bool		// content is rendered masked and cannot be copied
+//	password_char:			int|ident	// code point or first character of identifier used for masking, '*' by default
+//
+// Area is focusable by default and it is selected while it has focus. Area supports usual
+// editing shortcuts, Shift extends the selection, Ctrl+arrows jump over words, Home and
+// End move to line boundaries (with Ctrl to content boundaries), Ctrl+A selects all,
+// Ctrl+C/X/V copy, cut and paste, Ctrl+Z undoes and Ctrl+Y or Ctrl+Shift+Z redoes. Typing
+// and deleting are merged into one undo step until the cursor moves.
+//
+// Every edit is checked by validators from style and Area.Validators before it is applied,
+// rejected edit does not change the content and invokes Invalid event with the error
+// instead of TextChanged.
 type Area struct {
 	Text
 	HoldMap
 
 	drw CursorDrawer
 
-	selected, dirty, noEffects, shown bool
+	selected, dirty, noEffects, shown, TabInput bool
+
+	LineIdx, Line, UndoLimit int
+
+	history history
 
-	LineIdx, Line int
+	// Validators are checked after validators from style, they are kept when style reloads
+	Validators []Validator
+	styled     []Validator
 
 	Blinker         timer.Timer
 	CursorThickness float64
@@ -49,13 +76,31 @@ func (a *Area) New() Module {
 // Init implements Module interface
 func (a *Area) Init(e *Element) {
 	a.Composed = true // important for next call
+	content, reinit := a.Content, a.Element != nil
 	a.Text.Init(e)
+	if reinit {
+		// reloading style should not discard what user wrote
+		a.Content = content
+	}
 
 	a.drw = a.CursorDrawer("cursor_drawer", a.Scene.Assets.Cursors, defaultCursor{})
 	a.CursorThickness = e.Float("cursor_thickness", 2)
 	a.CursorMask = e.RGBA("cursor_mask", mat.White)
 	a.Blinker = timer.Period(e.Float("cursor_blinking_frequency", .6))
 
+	a.TabInput = e.Bool("tab_input", false)
+	a.UndoLimit = e.Int("undo_limit", 100)
+	a.initValidators(e)
+
+	a.Password = 0
+	if e.Bool("password", false) {
+		a.Password = rune(e.Int("password_char", '*'))
+		for _, r := range e.Ident("password_char", "") {
+			a.Password = r
+			break
+		}
+	}
+
 	a.AutoFrequency = e.Float("auto_frequency", .03)
 	a.HoldResponceSpeed = e.Float("hold_responce_speed", .5)
 
@@ -63,9 +108,18 @@ func (a *Area) Init(e *Element) {
 }
 
 // Update implements Module interface
-func (a *Area) Update(w *ggl.Window, delta float64) {
+func (a *Area) Update(w input.Input, delta float64) {
+	anchor, cursor := a.Start, a.End
+	ctrl, shift := Ctrl(w), Shift(w)
 	// Text.Update sets up lot of things
 	a.Text.Update(w, delta)
+	if a.selected && shift && a.Hovering && w.JustPressed(key.MouseLeft) {
+		a.Start = anchor // shift click extends the selection
+	}
+	if a.Start != anchor || a.End != cursor {
+		a.moved()
+	}
+
 	if a.Blinker.Period < 0 || w.Pressed(key.MouseLeft) && a.Start != a.End {
 		a.shown = true
 	} else {
@@ -75,98 +129,123 @@ func (a *Area) Update(w *ggl.Window, delta float64) {
 		}
 	}
 
-	if !a.selected && !a.Hovering {
+	if !a.selected {
 		return
 	}
 
-	// we don't want effects to be applied when user is editing text
-	if w.JustPressed(key.MouseLeft) {
-		if a.selected && !a.Hovering {
-			a.NoEffects = a.noEffects
-			a.selected = false
-			a.Dirty()
-			a.Events.Invoke(Deselect, nil)
-		} else if !a.selected && a.Hovering {
-			a.noEffects = a.NoEffects
-			a.NoEffects = true
-			a.selected = true
-			a.Dirty()
-			a.Events.Invoke(Select, nil)
-			// returning as text has to get redrawn and reindexed so we can restore the cursor
-			return
-		}
+	if a.dirty {
+		a.dirty = false
+		a.updateCursor()
 	}
 
-	if !a.selected {
-		return
-	}
+	start, end := a.Selection()
+	collapse := start != end && !shift
 
 	// it is shortest way to handle arrow navigation
-	if (a.Line > 0 && a.Hold(key.Up, w, delta, func() {
-		a.Line--
-		a.Start = a.ProjectLine(a.LineIdx, a.Line)
-	})) || (a.Line < a.Lines()-1 && a.Hold(key.Down, w, delta, func() {
-		a.Line++
-		a.Start = a.ProjectLine(a.LineIdx, a.Line)
-	})) || (a.Start > 0 && a.Hold(key.Left, w, delta, func() {
-		a.Start--
-	})) || (a.Start < len(a.Content) && a.Hold(key.Right, w, delta, func() {
-		a.Start++
-	})) || a.dirty {
-		a.LineIdx, a.Line = a.UnprojectLine(a.Start)
-		a.End = a.Start
-		a.dirty = false
-		a.Blinker.Reset()
-		a.shown = true
-		a.Scene.Redraw.Notify()
+	move := func(k key.Key, cond bool, to func() int) bool {
+		return cond && a.Hold(k, w, delta, func() {
+			a.moveCursor(to(), shift)
+		})
 	}
+	_ = move(key.Up, a.Line > 0, func() int {
+		return a.ProjectLine(a.LineIdx, a.Line-1)
+	}) || move(key.Down, a.Line < a.Lines()-1, func() int {
+		return a.ProjectLine(a.LineIdx, a.Line+1)
+	}) || move(key.Left, a.End > 0 || collapse, func() int {
+		switch {
+		case collapse:
+			return start
+		case ctrl:
+			return WordLeft(a.Content, a.End)
+		}
+		return a.End - 1
+	}) || move(key.Right, a.End < len(a.Content) || collapse, func() int {
+		switch {
+		case collapse:
+			return end
+		case ctrl:
+			return WordRight(a.Content, a.End)
+		}
+		return a.End + 1
+	}) || move(key.Home, true, func() int {
+		if ctrl {
+			return 0
+		}
+		return a.lineStart()
+	}) || move(key.End, true, func() int {
+		if ctrl {
+			return len(a.Content)
+		}
+		return a.lineEnd()
+	})
 
-	typed := w.Typed()
+	var (
+		typed = w.Typed()
+		kind  = editTyping
+	)
 
-	// cut paste thing
-	var cut bool
-	if w.Pressed(key.LeftControl) {
-		cut = w.JustPressed(key.X)
-		if a.Start != a.End && cut {
-			a.Scene.Log(a.Element, a.Clip(a.Start, a.End))
-		} else if w.JustPressed(key.V) {
+	if ctrl {
+		if w.JustPressed(key.A) {
+			a.SelectAll()
+		}
+		a.Hold(key.Z, w, delta, func() {
+			if shift {
+				a.Redo()
+			} else {
+				a.Undo()
+			}
+		})
+		a.Hold(key.Y, w, delta, func() {
+			a.Redo()
+		})
+		if w.JustPressed(key.X) && start != end && a.Password == 0 {
+			a.Scene.Log(a.Element, a.Clip(start, end))
+			a.replace(start, end, nil, editOther)
+		}
+		if w.JustPressed(key.V) {
 			var err error
 			typed, err = clipboard.ReadAll()
 			a.Scene.Log(a.Element, err)
+			kind = editOther
 		}
 	}
 
-	if !a.Hold(key.Enter, w, delta, func() {
-		typed = "\n"
+	a.Hold(key.Enter, w, delta, func() {
+		typed, kind = "\n", editOther
 		a.Events.Invoke(Enter, nil)
-	}) && !a.Hold(key.Backspace, w, delta, func() {
-		if a.Start != 0 && a.Start == a.End {
-			a.Start--
-		}
-	}) && !a.Hold(key.Tab, w, delta, func() {
-		typed = "\t"
-	}) && typed == "" && !cut {
-		return
+	})
+	if a.TabInput {
+		a.Hold(key.Tab, w, delta, func() {
+			typed = "\t"
+		})
 	}
+	a.Hold(key.Backspace, w, delta, func() {
+		start, end := a.Selection()
+		if start == end {
+			if ctrl {
+				start = WordLeft(a.Content, start)
+			} else {
+				start = mat.Maxi(start-1, 0)
+			}
+		}
+		a.replace(start, end, nil, editDeleting)
+	})
+	a.Hold(key.Delete, w, delta, func() {
+		start, end := a.Selection()
+		if start == end {
+			if ctrl {
+				end = WordRight(a.Content, end)
+			} else {
+				end = mat.Mini(end+1, len(a.Content))
+			}
+		}
+		a.replace(start, end, nil, editDeleting)
+	})
 
-	if cut { // we don't want to accidentally write something
-		typed = ""
-	}
-
-	a.Blinker.Reset()
-	a.shown = true
-
-	nv := str.NString(typed)
-	if a.Start > a.End {
-		a.Start, a.End = a.End, a.Start
+	if typed != "" {
+		start, end := a.Selection()
+		a.replace(start, end, str.NString(typed), kind)
 	}
-	a.Content.RemoveSlice(a.Start, a.End)
-	a.Content.InsertSlice(a.Start, nv)
-	a.Events.Invoke(TextChanged, typed)
-	a.Start += len(nv)
-	a.End = a.Start
-	a.Dirty()
-	a.dirty = true
 }
 
 // DrawOnTop implements Module interface
@@ -175,7 +254,7 @@ func (a *Area) DrawOnTop(tg ggl.Target, canvas *drw.Geom) {
 		a.drw.Draw(
 			tg,
 			canvas,
-			a.Dot(mat.Maxi(a.Start, a.End)).Sub(mat.V(0, a.Descent*a.Scl.Y)),
+			a.Dot(a.End).Sub(mat.V(0, a.Descent*a.Scl.Y)),
 			mat.V(a.CursorThickness, a.LineHeight*a.Scl.Y),
 			a.CursorMask,
 		)
@@ -184,6 +263,44 @@ func (a *Area) DrawOnTop(tg ggl.Target, canvas *drw.Geom) {
 	a.Text.DrawOnTop(tg, canvas)
 }
 
+// DefaultStyle implements Module interface
+func (a *Area) DefaultStyle() goss.Style {
+	s := a.Text.DefaultStyle()
+	s["tab_index"] = []interface{}{0}
+	return s
+}
+
+// OnFocus implements FocusHandler interface, area gets selected
+func (a *Area) OnFocus() {
+	// we don't want effects to be applied when user is editing text
+	a.noEffects = a.NoEffects
+	a.NoEffects = true
+	a.selected = true
+	a.Dirty()
+	a.Events.Invoke(Select, nil)
+}
+
+// OnBlur implements FocusHandler interface, area gets deselected
+func (a *Area) OnBlur() {
+	a.NoEffects = a.noEffects
+	a.selected = false
+	a.Dirty()
+	a.Events.Invoke(Deselect, nil)
+}
+
+// Captures implements KeyCapturer interface, arrows move the cursor
+func (a *Area) Captures(k key.Key) bool {
+	if k == key.Tab {
+		return a.TabInput
+	}
+	for _, d := range Directions {
+		if d.Key == k {
+			return true
+		}
+	}
+	return false
+}
+
 // Dirty is similar to Text.Dirty but it preserves the Start and End.
 func (a *Area) Dirty() {
 	start, end := a.Start, a.End
@@ -212,7 +329,7 @@ type HoldMap struct {
 }
 
 // Hold supports Hold and repeat effect, if you hold button for long enough, action starts repeating.
-func (h HoldMap) Hold(b key.Key, win *ggl.Window, delta float64, do func()) bool {
+func (h HoldMap) Hold(b key.Key, win input.Input, delta float64, do func()) bool {
 	if win.JustPressed(b) {
 		do()
 		return true
@@ -244,14 +361,19 @@ func (h HoldMap) Hold(b key.Key, win *ggl.Window, delta float64, do func()) bool
 // 	idle/hover/pressed/disabled+_region:	aabb|name	// sets region for each state
 //  all_padding:                			aabb	   	// sets padding on all states
 // 	idle/hover/pressed/disabled+_padding:	aabb		// sets padding for each state
+//
+// Button is focusable by default, focused button looks as if it was hovered and it can
+// be clicked by Enter or Space.
 type Button struct {
 	Patch
 
 	Text   Text
-	States [len(buttonStates)]ButtonState
+	States ButtonStates
+
+	Current                                ButtonStateEnum
+	selected, activated, applied, Disabled bool
 
-	Current            ButtonStateEnum
-	selected, Disabled bool
+	onClick func()
 }
 
 // New implements ModuleFactory interface
@@ -259,31 +381,17 @@ func (b *Button) New() Module {
 	return &Button{}
 }
 
+// DefaultStyle implements Module interface
+func (b *Button) DefaultStyle() goss.Style {
+	return goss.Style{
+		"tab_index": {0},
+	}
+}
+
 // Init implements Module interface
 func (b *Button) Init(e *Element) {
 	b.Patch.Init(e)
-
-	text := b.Raw.Attributes.Ident("all_text", "")
-	parsed := str.NString(text)
-	mask := b.RGBA("all_masks", mat.White)
-	region := e.Region("all_regions", e.Scene.Assets.Regions, mat.ZA)
-	padding := b.AABB("all_padding", mat.ZA)
-	for i := range b.States {
-		bs := &b.States[i]
-		bs.Mask = mask
-		bs.Region = region
-		bs.Padding = padding
-		bs.Text = parsed
-	}
-
-	for i, s := range buttonStates {
-		bs := &b.States[i]
-		bs.Text = str.NString(b.Raw.Attributes.Ident(s+"_text", text))
-		bs.Mask = b.RGBA(s+"_mask", bs.Mask)
-		bs.Region = e.Region(s+"_region", e.Scene.Assets.Regions, bs.Region)
-		bs.Padding = b.AABB(s+"_padding", bs.Padding)
-	}
-
+	b.States = ParseButtonStates(e, "", nil)
 }
 
 func (b *Button) PostInit() {
@@ -316,15 +424,31 @@ func (b *Button) PostInit() {
 }
 
 // Update implements Module interface
-func (b *Button) Update(w *ggl.Window, delta float64) {
+func (b *Button) Update(w input.Input, delta float64) {
 	if b.Disabled {
 		b.ApplyState(Disabled)
 		return
 	}
 
+	focused := b.Focused()
+	if !focused {
+		b.activated = false
+	} else if w.JustPressed(key.Enter) || w.JustPressed(key.Space) {
+		b.activated = true
+	} else if b.activated && (w.JustReleased(key.Enter) || w.JustReleased(key.Space)) {
+		b.activated = false
+		b.click()
+	}
+
 	if !b.Hovering {
-		b.ApplyState(Idle)
 		b.selected = false
+		if b.activated {
+			b.ApplyState(Pressed)
+		} else if focused {
+			b.ApplyState(Hover)
+		} else {
+			b.ApplyState(Idle)
+		}
 		return
 	}
 
@@ -334,18 +458,26 @@ func (b *Button) Update(w *ggl.Window, delta float64) {
 
 	if w.JustReleased(key.MouseLeft) {
 		if b.selected {
-			b.Events.Invoke(Click, nil)
+			b.click()
 		}
 		b.selected = false
 	}
 
-	if b.selected {
+	if b.selected || b.activated {
 		b.ApplyState(Pressed)
 	} else {
 		b.ApplyState(Hover)
 	}
 }
 
+// click invokes Click event, modules embedding button can hook in by onClick
+func (b *Button) click() {
+	if b.onClick != nil {
+		b.onClick()
+	}
+	b.Events.Invoke(Click, nil)
+}
+
 // ApplyState applies the button state by index
 func (b *Button) ApplyState(state ButtonStateEnum) {
 	if b.Current == state {
@@ -355,9 +487,28 @@ func (b *Button) ApplyState(state ButtonStateEnum) {
 	bs := &b.States[state]
 	b.Patch.Padding = bs.Padding
 	b.Patch.SetRegion(bs.Region)
-	b.Patch.Mask = bs.Mask
 	b.Text.Content = bs.Text
 	b.Text.Dirty()
+
+	from, to := b.Patch.Mask, bs.Mask
+	if !b.applied || from == to {
+		b.Patch.Mask = to
+		b.applied = true
+		return
+	}
+	b.Scene.Animate(b.Element, "mask", func(t float64) {
+		b.Patch.Mask = mat.LerpColor(from, to, t)
+		b.Patch.OnFrameChange()
+		b.Scene.Redraw.Notify()
+	}, nil)
+}
+
+// reapply forces current state to be applied again
+func (b *Button) reapply() {
+	if state := b.Current; state != None {
+		b.Current = None
+		b.ApplyState(state)
+	}
 }
 
 // SetText sets text on all states to given value
@@ -375,6 +526,42 @@ type ButtonState struct {
 	Text            str.String
 }
 
+// ButtonStates holds ButtonState for each ButtonStateEnum except None
+type ButtonStates [len(buttonStates)]ButtonState
+
+// ParseButtonStates parses states same way as Button does, only all keys are prefixed by prefix,
+// if base is not nil, states default to it
+func ParseButtonStates(e *Element, prefix string, base *ButtonStates) (states ButtonStates) {
+	text := func(key string, def str.String) str.String {
+		if _, ok := e.Raw.Attributes[key]; ok {
+			return str.NString(e.Raw.Attributes.Ident(key, ""))
+		}
+		return def
+	}
+
+	regions := e.Scene.Assets.Regions
+	for i, s := range buttonStates {
+		bs := &states[i]
+		if base != nil {
+			*bs = base[i]
+		} else {
+			bs.Mask = mat.White
+		}
+
+		bs.Text = text(prefix+"all_text", bs.Text)
+		bs.Mask = e.RGBA(prefix+"all_masks", bs.Mask)
+		bs.Region = e.Region(prefix+"all_regions", regions, bs.Region)
+		bs.Padding = e.AABB(prefix+"all_padding", bs.Padding)
+
+		bs.Text = text(prefix+s+"_text", bs.Text)
+		bs.Mask = e.RGBA(prefix+s+"_mask", bs.Mask)
+		bs.Region = e.Region(prefix+s+"_region", regions, bs.Region)
+		bs.Padding = e.AABB(prefix+s+"_padding", bs.Padding)
+	}
+
+	return
+}
+
 type ButtonStateEnum uint8
 
 const (
@@ -417,7 +604,7 @@ func (p *Patch) Init(e *Element) {
 	w, h := p.Region.W()*.5, p.Region.H()*.5
 	p.Padding = e.AABB("patch_padding", mat.A(w, h, w, h))
 
-	p.Scale = e.Vec("patch_scale", mat.V(1, 1))
+	p.Scale = e.Vec("patch_scale", mat.V(1, 1)).Scaled(e.Scene.Scale())
 
 	p.SetRegion(p.Region)
 }
@@ -508,7 +695,7 @@ type Scroll struct {
 	X, Y                                   Bar
 
 	offset, vel, ratio, corner mat.Vec
-	dirty, useVel              bool
+	dirty, useVel, initialized bool
 }
 
 // New implements ModuleFactory interface
@@ -533,8 +720,11 @@ func (s *Scroll) Init(e *Element) {
 		s.X.Use = c
 		s.Y.Use = c
 	}
-	s.X.position = 1 // to prevent snap
-	s.Y.position = 1
+	if !s.initialized {
+		s.X.position = 1 // to prevent snap
+		s.Y.position = 1
+		s.initialized = true
+	}
 }
 
 // DrawOnTop implements module interface
@@ -561,7 +751,7 @@ func (s *Scroll) DrawOnTop(t ggl.Target, c *drw.Geom) {
 }
 
 // Update implements module interface
-func (s *Scroll) Update(w *ggl.Window, delta float64) {
+func (s *Scroll) Update(w input.Input, delta float64) {
 	if s.dirty {
 		s.dirty = false
 		s.update()
@@ -620,7 +810,7 @@ func (s *Scroll) Update(w *ggl.Window, delta float64) {
 			s.X.Move(-move.X)
 			s.dirty = true
 		}
-	} else if !s.Scene.TextSelected {
+	} else if !s.Scene.TextSelected && s.Scene.Dragged() == nil {
 		s.vel.X = move.X
 		s.useVel = true
 	}
@@ -633,7 +823,7 @@ func (s *Scroll) Update(w *ggl.Window, delta float64) {
 			s.Y.Move(move.Y)
 			s.dirty = true
 		}
-	} else if !s.Scene.TextSelected {
+	} else if !s.Scene.TextSelected && s.Scene.Dragged() == nil {
 		s.vel.Y = move.Y
 		s.useVel = true
 	}
@@ -766,6 +956,10 @@ type Text struct {
 	dirty, Composed, selected bool
 	SelectionColor            mat.RGBA
 	Start, End, LineIdx, Line int
+	// Password makes text render this rune instead of each character if it is not 0,
+	// content stays intact and it cannot be copied, call Dirty after changing it
+	Password rune
+	width    float64
 }
 
 // New implements module factory interface
@@ -797,21 +991,22 @@ func (t *Text) Init(e *Element) {
 	if ident == "inherit" {
 		ident = "default"
 	}
-	mkd, ok := t.Scene.Assets.Markdowns[ident]
-	if !ok {
+	mkd, scale := t.Scene.Markdown(ident)
+	if mkd == nil {
 		panic(t.Path() + ": markdown with name '" + ident + "' is not present in assets")
 	}
 
 	t.Markdown = mkd
 	t.Align = t.Props.Align("text_align", txt.Left)
-	t.Scl = t.Vec("text_scale", mat.V(1, 1))
+	t.Scl = t.Vec("text_scale", mat.V(1, 1)).Scaled(t.Scene.Scale() / scale)
 	t.Mask = t.RGBA("text_color", mat.White)
 	t.SelectionColor = t.RGBA("text_selection_color", mat.Alpha(.5))
 	if !t.Composed {
-		t.Props.Size = t.Vec("text_size", mat.ZV)
-		t.Props.Margin = t.AABB("text_margin", mat.A(4, 4, 4, 4))
+		m := t.scaled(4)
+		t.Props.Size = t.scaledVec("text_size", mat.ZV)
+		t.Props.Margin = t.scaledAABB("text_margin", mat.A(m, m, m, m))
 		t.Background = t.RGBA("text_background", t.Background)
-		t.Props.Padding = t.AABB("text_padding", mat.ZA)
+		t.Props.Padding = t.scaledAABB("text_padding", mat.ZA)
 	}
 	t.NoEffects = t.Bool("text_no_effects", false)
 	t.Content = str.NString(t.Raw.Attributes.Ident("text", string(t.Content)))
@@ -826,7 +1021,7 @@ func (t *Text) Draw(tr ggl.Target, g *drw.Geom) {
 }
 
 // Update implements Module interface
-func (t *Text) Update(w *ggl.Window, delta float64) {
+func (t *Text) Update(w input.Input, delta float64) {
 	t.Paragraph.Update(delta)
 	if t.Changes() {
 		t.Scene.Redraw.Notify()
@@ -837,7 +1032,7 @@ func (t *Text) Update(w *ggl.Window, delta float64) {
 		start, end = end, start
 	}
 
-	if w.Pressed(key.LeftControl) {
+	if Ctrl(w) {
 		if start != end && w.JustPressed(key.C) {
 			t.Scene.Log(t.Element, t.Clip(start, end))
 		}
@@ -917,15 +1112,27 @@ func (t *Text) Height(takable, taken float64) float64 {
 
 // UpdateParagraph updates the paragraph to fit given width, though can end up bigger
 func (t *Text) UpdateParagraph(width float64) {
-	if t.dirty || width != t.Paragraph.Width*t.Scl.X {
+	// comparing against Paragraph.Width*Scl would re-wrap every time because of rounding
+	if t.dirty || width != t.width {
+		t.width = width
 		t.Paragraph.Width = width / t.Scl.X
-		t.Markdown.Parse(&t.Paragraph)
+		if t.Password != 0 {
+			content := t.Content
+			t.Content = mask(content, t.Password)
+			t.Markdown.Parse(&t.Paragraph)
+			t.Content = content
+		} else {
+			t.Markdown.Parse(&t.Paragraph)
+		}
 		t.dirty = false
 	}
 }
 
-// Clip copies the range into clipboard
+// Clip copies the range into clipboard, it does nothing if text is password
 func (t *Text) Clip(start, end int) error {
+	if t.Password != 0 {
+		return nil
+	}
 	return clipboard.WriteAll(string(t.Compiled[start:end]))
 }
 
@@ -935,9 +1142,10 @@ func (t *Text) SetText(text string) {
 	t.Dirty()
 }
 
-// Dirty forces text to update
+// Dirty forces text to update, only layout boundary of text is resized, see
+// Element.Relayout
 func (t *Text) Dirty() {
 	t.dirty = true
 	t.Start, t.End = 0, 0
-	t.Scene.Resize.Notify()
+	t.Relayout()
 }
@@ -0,0 +1,175 @@
+package ui
+
+import (
+	"github.com/jakubDoka/mlok/ggl/drw"
+	"github.com/jakubDoka/mlok/ggl/input"
+	"github.com/jakubDoka/mlok/ggl/key"
+	"github.com/jakubDoka/mlok/mat"
+)
+
+// MouseEvent is data passed to mouse events. MouseDown, MouseUp, MouseClick and MouseWheel
+// are invoked on the deepest element under the mouse and bubble trough its parents until
+// some listener calls Stop:
+//
+//	e.Listen(ui.MouseClick, func(i interface{}) {
+//		ev := i.(*ui.MouseEvent)
+//		if ev.Button == key.MouseRight {
+//			openMenu(ev.Pos)
+//			ev.Stop() // parents will not receive the click
+//		}
+//	})
+//
+// MouseEntered and MouseExited do not bubble, element is hovered while it or any of its
+// children is under the mouse.
+type MouseEvent struct {
+	// Target is element event was invoked on, Current is element whose listeners are
+	// being called
+	Target, Current *Element
+	Pos             mat.Vec
+	// Button is mouse button of MouseDown, MouseUp and MouseClick
+	Button key.Key
+	// Scroll is scroll of MouseWheel
+	Scroll mat.Vec
+
+	stopped bool
+}
+
+// Stop stops the propagation of event to parents
+func (m *MouseEvent) Stop() {
+	m.stopped = true
+}
+
+// Stopped returns whether Stop was called
+func (m *MouseEvent) Stopped() bool {
+	return m.stopped
+}
+
+// ElementAt returns deepest visible element under the pos, later children are on top of
+// earlier ones and children of Scroll are hit only inside its viewport, nil is returned if
+// there is no element under the pos. Layers are searched from the top, empty space of
+// layer is transparent unless the layer is modal, then its root is returned. TooltipLayer
+// is never hit so tooltips do not steal the mouse.
+func (s *Scene) ElementAt(pos mat.Vec) *Element {
+	modal := s.ModalLayer()
+	for l := layerCount - 1; l > modal; l-- {
+		if l == TooltipLayer {
+			continue
+		}
+		if hit := s.Layer(l).elementAt(pos); hit != nil && hit != s.Layer(l) {
+			return hit
+		}
+	}
+	if hit := s.Layer(modal).elementAt(pos); hit != nil || modal == BaseLayer {
+		return hit
+	}
+	return s.Layer(modal)
+}
+
+// Hovered returns element under the mouse from last update
+func (s *Scene) Hovered() *Element {
+	if len(s.hovered) == 0 {
+		return nil
+	}
+	return s.hovered[0]
+}
+
+// Bubble invokes the event on e and its parents until ev gets stopped
+func (s *Scene) Bubble(e *Element, name string, ev *MouseEvent) {
+	ev.Target = e
+	for ; e != nil && !ev.stopped; e = e.Parent {
+		ev.Current = e
+		e.Events.Invoke(name, ev)
+	}
+}
+
+// elementAt is recursive part of ElementAt
+func (e *Element) elementAt(pos mat.Vec) (hit *Element) {
+	if vp, ok := e.Proc.(*drw.SpriteViewport); !ok || vp.Area.Contains(pos) {
+		e.forChild(IgnoreHiddenReverse, func(ch *Element) {
+			if hit == nil {
+				hit = ch.elementAt(pos)
+			}
+		})
+	}
+
+	if hit == nil && e.Frame.Contains(pos) {
+		hit = e
+	}
+
+	return
+}
+
+// updateMouse performs hit testing and dispatches mouse events
+func (s *Scene) updateMouse(w input.Input, delta float64) {
+	pos := w.MousePos()
+	hit := s.ElementAt(pos)
+	s.hover(hit, pos)
+	s.updateTooltip(w, delta)
+	s.updateDrag(w, hit, pos)
+
+	for b := key.Mouse1; b <= key.MouseLast; b++ {
+		if w.JustPressed(b) {
+			s.dismiss(hit)
+			s.down[b] = hit
+			s.Bubble(hit, MouseDown, &MouseEvent{Pos: pos, Button: b})
+		}
+		if w.JustReleased(b) {
+			s.Bubble(hit, MouseUp, &MouseEvent{Pos: pos, Button: b})
+			// click goes to closest element that was both pressed and released
+			if c := commonParent(s.down[b], hit); c != nil && c.Scene == s {
+				s.Bubble(c, MouseClick, &MouseEvent{Pos: pos, Button: b})
+			}
+			s.down[b] = nil
+		}
+	}
+
+	if scroll := w.MouseScroll(); scroll != mat.ZV {
+		s.Bubble(hit, MouseWheel, &MouseEvent{Pos: pos, Scroll: scroll})
+	}
+}
+
+// hover updates hovered elements and invokes MouseExited and MouseEntered
+func (s *Scene) hover(hit *Element, pos mat.Vec) {
+	for _, e := range s.hovered {
+		if !e.isParentOf(hit) {
+			e.Hovering = false
+			if e.Scene == s {
+				e.Events.Invoke(MouseExited, &MouseEvent{Target: e, Current: e, Pos: pos})
+			}
+		}
+	}
+
+	s.hovered = s.hovered[:0]
+	for e := hit; e != nil; e = e.Parent {
+		s.hovered = append(s.hovered, e)
+	}
+
+	// parents get entered first
+	for i := len(s.hovered) - 1; i >= 0; i-- {
+		e := s.hovered[i]
+		if !e.Hovering {
+			e.Hovering = true
+			e.Events.Invoke(MouseEntered, &MouseEvent{Target: e, Current: e, Pos: pos})
+		}
+	}
+}
+
+// isParentOf returns whether e is ch or any of its parents
+func (e *Element) isParentOf(ch *Element) bool {
+	for ; ch != nil; ch = ch.Parent {
+		if ch == e {
+			return true
+		}
+	}
+	return false
+}
+
+// commonParent returns deepest element that is parent of both a and b or nil
+func commonParent(a, b *Element) *Element {
+	for ; a != nil; a = a.Parent {
+		if a.isParentOf(b) {
+			return a
+		}
+	}
+	return nil
+}
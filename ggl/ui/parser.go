@@ -27,18 +27,21 @@ func (*NoFactory) New() Module { return nil }
 // Parser handles element parsing form goml, if you don't know goml syntax read
 // the tutorial on github.com/jakubDoka/goml
 type Parser struct {
-	factories map[string]ModuleFactory
-	GP        *goml.Parser
-	GS        goss.Parser
+	factories  map[string]ModuleFactory
+	components map[string]Component
+	GP         *goml.Parser
+	GS         goss.Parser
 }
 
 // NParser creates ready-to-use Parser
 func NParser() *Parser {
 	p := &Parser{
-		factories: map[string]ModuleFactory{},
+		factories:  map[string]ModuleFactory{},
+		components: map[string]Component{},
 	}
 
 	p.GP = goml.NParser(&p.GS)
+	p.GP.AddDefinitions("component", "slot")
 
 	p.AddFactory("div", &ModuleBase{})
 	p.AddFactory("text", &Text{})
@@ -47,6 +50,11 @@ func NParser() *Parser {
 	p.AddFactory("patch", &Patch{})
 	p.AddFactory("button", &Button{})
 	p.AddFactory("area", &Area{})
+	p.AddFactory("checkbox", &Checkbox{})
+	p.AddFactory("radio_group", &RadioGroup{})
+	p.AddFactory("slider", &Slider{})
+	p.AddFactory("dropdown", &Dropdown{})
+	p.AddFactory("list", &List{})
 
 	return p
 }
@@ -58,24 +66,40 @@ func (p *Parser) AddFactory(name string, mf ModuleFactory) {
 	p.GP.AddDefinitions(name)
 }
 
-// Parse parses goml source into list of elements
+// Parse parses goml source into list of elements, component definitions are registered
+// and they are not part of the list, see Component
 func (p *Parser) Parse(source []byte) ([]*Element, error) {
 	div, err := p.GP.Parse(source)
 	if err != nil {
 		return nil, ErrGoml.Wrap(err)
 	}
-	elems := make([]*Element, len(div.Children))
-	for i, e := range div.Children {
-		ch, err := p.translateElement(i, e)
+	elems := make([]*Element, 0, len(div.Children))
+	for _, e := range div.Children {
+		if e.Name == "component" {
+			if err := p.addComponent(e); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		ch, err := p.translateElement(len(elems), e)
 		if err != nil {
 			return nil, err
 		}
-		elems[i] = ch
+		elems = append(elems, ch)
 	}
 	return elems, nil
 }
 
 func (p *Parser) translateElement(i int, elem goml.Element) (*Element, error) {
+	return p.translate(i, elem, nil)
+}
+
+// translate translates element, slot is content of slot elements inside component template
+func (p *Parser) translate(i int, elem goml.Element, slot *slotContent) (*Element, error) {
+	if c, ok := p.components[elem.Name]; ok {
+		return p.expand(i, c, elem, slot)
+	}
+
 	val, ok := p.factories[elem.Name]
 	if !ok {
 		return nil, ErrMissingFactory.Args(elem.Name)
@@ -97,6 +121,16 @@ func (p *Parser) translateElement(i int, elem goml.Element) (*Element, error) {
 	if val, ok := elem.Attributes["group"]; ok {
 		e.group = val[0]
 	}
+	if val, ok := elem.Attributes["tab_index"]; ok {
+		idx, err := strconv.Atoi(val[0])
+		if err != nil {
+			return nil, ErrUrlp.Wrap(err)
+		}
+		if e.Raw.Style == nil {
+			e.Raw.Style = goss.Style{}
+		}
+		e.Raw.Style["tab_index"] = []interface{}{idx}
+	}
 	if val, ok := elem.Attributes["styles"]; ok {
 		if len(val) == 1 && strings.Contains(val[0], " ") { // make it more friendly, both list and string is valid
 			e.Styles = strings.Split(val[0], " ")
@@ -105,15 +139,33 @@ func (p *Parser) translateElement(i int, elem goml.Element) (*Element, error) {
 		}
 	}
 
-	for i, ch := range elem.Children {
-		el, err := p.translateElement(i, ch)
+	if err := p.translateChildren(e, elem.Children, slot, new(int)); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// translateChildren translates children and adds them to e, slot elements are replaced
+// by content of slot, i is index of next child
+func (p *Parser) translateChildren(e *Element, children []goml.Element, slot *slotContent, i *int) error {
+	for _, ch := range children {
+		if ch.Name == "slot" && slot != nil {
+			// slot content belongs to the scope where component was used
+			if err := p.translateChildren(e, slot.children, slot.outer, i); err != nil {
+				return err
+			}
+			continue
+		}
+
+		el, err := p.translate(*i, ch, slot)
 		if err != nil {
-			return nil, ErrPath.Args(e.name).Wrap(err)
+			return ErrPath.Args(e.name).Wrap(err)
 		}
 		e.AddChild(el.name, el)
+		*i++
 	}
-
-	return e, nil
+	return nil
 }
 
 // ModuleFactory should be an producer of module instances for parser
@@ -6,8 +6,11 @@ import (
 
 	"github.com/jakubDoka/mlok/ggl"
 	"github.com/jakubDoka/mlok/ggl/drw"
+	"github.com/jakubDoka/mlok/ggl/input"
+	"github.com/jakubDoka/mlok/ggl/key"
 	"github.com/jakubDoka/mlok/ggl/pck"
 	"github.com/jakubDoka/mlok/ggl/txt"
+	"github.com/jakubDoka/mlok/load"
 	"github.com/jakubDoka/mlok/mat"
 	"github.com/jakubDoka/sterr"
 
@@ -23,8 +26,11 @@ type Processor struct {
 	frame  mat.AABB
 	canvas drw.Geom
 
+	scale     float64
+	scaleBase mat.Vec
+
 	margins, relativeMargins []*float64
-	filled                   []*Element
+	filled, bounds           []*Element
 	verticalFormatter        VerticalFormatter
 	horizontalFormatter      HorizontalFormatter
 }
@@ -35,6 +41,7 @@ type Processor struct {
 func (p *Processor) SetScene(s *Scene) {
 	p.scene = s
 	s.Resize.Notify()
+	p.updateMedia()
 }
 
 // Fetch passes triangles to given target
@@ -66,6 +73,7 @@ func (p *Processor) SetFrame(value mat.AABB) {
 	if p.frame != value {
 		p.frame = value
 		p.scene.Resize.Notify()
+		p.updateMedia()
 	}
 }
 
@@ -79,13 +87,37 @@ func (p *Processor) SetFrame(value mat.AABB) {
 // note that resizing also triggers consequent Redrawing
 //
 // panics if scene is not set
-func (p *Processor) Update(w *ggl.Window, delta float64) {
+func (p *Processor) Update(w input.Input, delta float64) {
 	p.assertScene()
 
-	p.scene.Root.update(p, w, delta)
+	p.scene.pollReload()
+	p.scene.Sync()
+	p.scene.animate(delta)
+	p.scene.updateMouse(w, delta)
+	modal := p.scene.ModalLayer()
+	for l := BaseLayer; l < layerCount; l++ {
+		if l < modal {
+			p.scene.Layer(l).update(p, &noInput, delta)
+		} else {
+			p.scene.Layer(l).update(p, w, delta)
+		}
+	}
+	p.scene.updateFocus(w)
 	if p.scene.Resize.Should() {
 		p.Resize()
 	}
+	// modules can change their children during layout (List does) and relayout them
+	for i := 0; i < maxRelayouts && len(p.scene.relayout) != 0; i++ {
+		p.relayout()
+	}
+	// shown elements know their size only after resize
+	if len(p.scene.appearing) != 0 {
+		for _, e := range p.scene.appearing {
+			e.appear()
+		}
+		p.scene.appearing = p.scene.appearing[:0]
+		p.Resize()
+	}
 	if p.scene.Redraw.Should() {
 		p.Redraw()
 	}
@@ -100,8 +132,12 @@ func (p *Processor) Redraw() {
 	p.assertScene()
 
 	p.scene.TextSelected = false
+	p.scene.drawGen++
 	p.scene.Batch.Clear()
-	p.scene.Root.redraw(&p.scene.Batch, &p.canvas)
+	p.scene.forLayers(func(l *Element) {
+		l.redraw(&p.scene.Batch, &p.canvas)
+	})
+	p.scene.drawGhost(&p.scene.Batch, &p.canvas)
 	p.scene.Redraw.Done()
 }
 
@@ -113,15 +149,24 @@ func (p *Processor) Redraw() {
 func (p *Processor) Resize() {
 	p.assertScene()
 
-	p.resize(&p.scene.Root, p.frame.W(), &p.horizontalFormatter, X)
-	p.resize(&p.scene.Root, p.frame.H(), &p.verticalFormatter, Y)
-
-	p.scene.Root.move(p.frame.Min, false)
+	// relayouts requested during resize are kept
+	p.scene.relayout = p.scene.relayout[:0]
+	p.scene.forLayers(p.resizeLayer)
 
 	p.scene.Resize.Done()
 	p.scene.Redraw.Notify()
 }
 
+// resizeLayer resizes root of layer to processor frame
+func (p *Processor) resizeLayer(l *Element) {
+	l.resolveSize(p.frame.W(), X)
+	p.resize(l, p.frame.W(), &p.horizontalFormatter, X)
+	l.resolveSize(p.frame.H(), Y)
+	p.resize(l, p.frame.H(), &p.verticalFormatter, Y)
+
+	l.move(p.frame.Min, false)
+}
+
 // assertScene panics if processor scene is nil
 func (p *Processor) assertScene() {
 	if p.scene == nil {
@@ -147,6 +192,14 @@ func (p *Processor) resize(e *Element, takable float64, formatter Formatter, dim
 	}
 
 	takable -= formatter.Sum(e.Padding)
+	if size == Fill {
+		takable = e.clamp(takable, dim)
+	}
+
+	s := e.children.Slice()
+	for i := 0; i < len(s); i++ {
+		s[i].Value.resolveSize(takable, dim)
+	}
 
 	// splitter performs space splitting between len targets and
 	// prevents negative sizes
@@ -167,8 +220,17 @@ func (p *Processor) resize(e *Element, takable float64, formatter Formatter, dim
 		return sz
 	}
 
-	s := e.children.Slice()
-	if formatter.Condition(e.Horizontal()) { // resolving public sizes
+	// wrap and grid compositions do not expand over the size, if it is specified
+	limit := takable
+	if size != Fill && size > 0 {
+		limit = math.Min(limit, size-formatter.Sum(e.Padding))
+	}
+
+	if e.Composition == Grid {
+		taken = p.grid(e, takable, limit, formatter, dim)
+	} else if e.Wraps() {
+		taken = p.wrap(e, takable, limit, formatter, dim)
+	} else if formatter.Condition(e.Horizontal()) { // resolving public sizes
 		e.processed = e.processed[:0]
 		// sizes that are not fill, also relative sizes
 		for i := 0; i < len(s); i++ {
@@ -302,6 +364,304 @@ func (p *Processor) resize(e *Element, takable float64, formatter Formatter, dim
 	return *ptr + formatter.Sum(e.Margin)
 }
 
+// wrap resizes children of element with wrap composition, children are broken into lines
+// during main axis pass, line is broken when adding child would exceed the limit. Cross axis
+// pass then sizes the lines. Vertical wrap has main axis resolved after the cross axis so it
+// resolves cross axis again when lines are known.
+func (p *Processor) wrap(e *Element, takable, limit float64, formatter Formatter, dim Dimension) (taken float64) {
+	// filler has same purpose as in resize
+	filler := func(ch *Element) float64 {
+		formatter.Set(ch)
+		sz := formatter.Size()
+		if sz == Fill || ch.Expands(dim) {
+			return takable
+		}
+		return sz + formatter.Sum(ch.Margin)
+	}
+
+	// offer is what child gets offered inside the line
+	offer := func(ch *Element, space float64) float64 {
+		formatter.Set(ch)
+		if sz := formatter.Size(); sz != Fill {
+			return sz + formatter.Sum(ch.Margin)
+		}
+		return space
+	}
+
+	e.processed = e.processed[:0]
+	s := e.children.Slice()
+	for i := 0; i < len(s); i++ {
+		ch := s[i].Value
+		if ch.hidden {
+			continue
+		}
+		if ch.Relative {
+			p.resize(ch, filler(ch), formatter, dim)
+			p.fillMargins(ch, formatter, takable)
+			continue
+		}
+		e.processed = append(e.processed, ch)
+	}
+
+	if !formatter.Condition(e.Horizontal()) {
+		if dim == X { // lines are not known yet
+			for _, ch := range e.processed {
+				taken = math.Max(p.resize(ch, offer(ch, takable), formatter, dim), taken)
+				p.fillMargins(ch, formatter, 0)
+			}
+			return
+		}
+		return p.wrapCross(e, formatter, dim)
+	}
+
+	gap := e.Gap.X
+	if dim == Y {
+		gap = e.Gap.Y
+	}
+
+	e.lines = e.lines[:0]
+	var line wrapLine
+	for i, ch := range e.processed {
+		sz := p.resize(ch, offer(ch, limit), formatter, dim)
+		if i != line.start && line.main+gap+sz > limit {
+			e.lines = append(e.lines, line)
+			line = wrapLine{start: i}
+		}
+		if i != line.start {
+			line.main += gap
+		}
+		line.main += sz
+		line.end = i + 1
+	}
+	if len(e.processed) != 0 {
+		e.lines = append(e.lines, line)
+	}
+
+	// fill margins take the remaining space of line
+	for i := range e.lines {
+		l := &e.lines[i]
+		p.margins = p.margins[:0]
+		for _, ch := range e.processed[l.start:l.end] {
+			formatter.Set(ch)
+			for _, v := range formatter.MarginPtr() {
+				if *v == Fill {
+					p.margins = append(p.margins, v)
+				}
+			}
+		}
+
+		if len(p.margins) != 0 {
+			split := math.Max((limit-l.main)/float64(len(p.margins)), 0)
+			for _, v := range p.margins {
+				*v = split
+			}
+			l.main += split * float64(len(p.margins))
+		}
+
+		taken = math.Max(taken, l.main)
+	}
+
+	if dim == Y { // vertical wrap has to resolve width again
+		cross := p.wrapCross(e, &p.horizontalFormatter, X)
+		e.ChildSize.X = cross
+		if e.Expands(X) {
+			e.size.X = math.Max(e.size.X, cross+fill.hSum(e.Padding))
+		}
+		// children could have been stretched so they need to adapt
+		for _, ch := range e.processed {
+			if e.Alignment == AlignStretch || ch.Props.Size.X == Fill {
+				p.resize(ch, offer(ch, limit), formatter, dim)
+			}
+		}
+	}
+
+	return
+}
+
+// wrapCross resolves cross axis of lines, returned value is space all lines take
+func (p *Processor) wrapCross(e *Element, formatter Formatter, dim Dimension) (taken float64) {
+	gap := e.Gap.X
+	if dim == Y {
+		gap = e.Gap.Y
+	}
+
+	for i := range e.lines {
+		l := &e.lines[i]
+		line := e.processed[l.start:l.end]
+
+		l.cross = 0
+		for _, ch := range line {
+			formatter.Set(ch)
+			if sz := formatter.Size(); sz != Fill {
+				l.cross = math.Max(l.cross, p.resize(ch, sz+formatter.Sum(ch.Margin), formatter, dim))
+			}
+		}
+
+		for _, ch := range line {
+			formatter.Set(ch)
+			if formatter.Size() == Fill {
+				p.resize(ch, l.cross, formatter, dim)
+			} else if e.Alignment == AlignStretch {
+				p.stretch(ch, l.cross, formatter, dim)
+			}
+			p.fillMargins(ch, formatter, l.cross)
+		}
+
+		if i != 0 {
+			taken += gap
+		}
+		taken += l.cross
+	}
+
+	return
+}
+
+// grid resizes children of element with grid composition, tracks of given dimension are
+// resolved in order: fixed, auto and fill, then children are offered the space of cells
+// they span, fill tracks split what remains from the limit
+func (p *Processor) grid(e *Element, takable, limit float64, formatter Formatter, dim Dimension) (taken float64) {
+	gap, template := e.Gap.X, e.Columns
+	if dim == Y {
+		gap, template = e.Gap.Y, e.Rows
+	}
+
+	e.processed = e.processed[:0]
+	s := e.children.Slice()
+	for i := 0; i < len(s); i++ {
+		ch := s[i].Value
+		if ch.hidden {
+			continue
+		}
+		if ch.Relative {
+			formatter.Set(ch)
+			sz := formatter.Size()
+			if sz == Fill || ch.Expands(dim) {
+				sz = takable
+			} else {
+				sz += formatter.Sum(ch.Margin)
+			}
+			p.resize(ch, sz, formatter, dim)
+			p.fillMargins(ch, formatter, takable)
+			continue
+		}
+		e.processed = append(e.processed, ch)
+	}
+
+	if dim == X { // x is always resolved first
+		e.place()
+	}
+
+	count := len(template)
+	for _, ch := range e.processed {
+		count = mat.Maxi(count, ch.cell[dim]+ch.Span[dim])
+	}
+
+	mode := func(i int) TrackMode {
+		if i < len(template) {
+			return template[i].Mode
+		}
+		return TrackAuto
+	}
+
+	tracks := e.tracks[dim][:0]
+	var fixed, weights float64
+	for i := 0; i < count; i++ {
+		tracks = append(tracks, 0)
+		switch mode(i) {
+		case TrackPixels:
+			tracks[i] = template[i].Value
+		case TrackFill:
+			weights += template[i].Value
+		}
+	}
+
+	for _, ch := range e.processed {
+		i := ch.cell[dim]
+		formatter.Set(ch)
+		if sz := formatter.Size(); ch.Span[dim] == 1 && mode(i) == TrackAuto && sz != Fill {
+			tracks[i] = math.Max(tracks[i], p.resize(ch, sz+formatter.Sum(ch.Margin), formatter, dim))
+		}
+	}
+
+	for i, t := range tracks {
+		if mode(i) != TrackFill {
+			fixed += t
+		}
+	}
+	if count > 1 {
+		fixed += gap * float64(count-1)
+	}
+
+	if weights != 0 {
+		unit := math.Max((limit-fixed)/weights, 0)
+		for i := range tracks {
+			if mode(i) == TrackFill {
+				tracks[i] = unit * template[i].Value
+			}
+		}
+	}
+
+	for _, ch := range e.processed {
+		start, end := ch.cell[dim], ch.cell[dim]+ch.Span[dim]
+		cell := gap * float64(end-start-1)
+		for _, t := range tracks[start:end] {
+			cell += t
+		}
+		p.resize(ch, cell, formatter, dim)
+		p.fillMargins(ch, formatter, cell)
+	}
+
+	for _, t := range tracks {
+		taken += t
+	}
+	if count > 1 {
+		taken += gap * float64(count-1)
+	}
+
+	e.tracks[dim] = tracks
+
+	return
+}
+
+// stretch resizes element as if its size was Fill
+func (p *Processor) stretch(e *Element, takable float64, formatter Formatter, dim Dimension) {
+	ptr := &e.Props.Size.X
+	if dim == Y {
+		ptr = &e.Props.Size.Y
+	}
+	size := *ptr
+	*ptr = Fill
+	p.resize(e, takable, formatter, dim)
+	*ptr = size
+}
+
+// fillMargins splits free space between fill margins of element
+func (p *Processor) fillMargins(e *Element, formatter Formatter, space float64) {
+	formatter.Set(e)
+	p.relativeMargins = p.relativeMargins[:0]
+	for _, v := range formatter.MarginPtr() {
+		if *v == Fill {
+			p.relativeMargins = append(p.relativeMargins, v)
+		}
+	}
+
+	if len(p.relativeMargins) == 0 {
+		return
+	}
+
+	split := math.Max((space-*formatter.Ptr()-formatter.Sum(e.Margin))/float64(len(p.relativeMargins)), 0)
+	for _, v := range p.relativeMargins {
+		*v = split
+	}
+}
+
+// wrapLine stores line of children in wrap composition, start and end
+// are indexes into Element.processed
+type wrapLine struct {
+	start, end  int
+	main, cross float64
+}
+
 // HorizontalFormatter is used when resolving Horizontal sizes and margins
 // methods are not documented, for doc look for Formatter interface
 type HorizontalFormatter struct {
@@ -315,7 +675,7 @@ func (r *HorizontalFormatter) Ptr() *float64 { return &r.size.X }
 func (r *HorizontalFormatter) Sum(a mat.AABB) float64 { return fill.hSum(a) }
 
 func (r *HorizontalFormatter) Provide(takable, taken float64) float64 {
-	return r.Module.Width(takable, taken)
+	return r.clamp(r.Module.Width(takable, taken), X)
 }
 
 func (r *HorizontalFormatter) MarginPtr() [2]*float64 {
@@ -340,7 +700,7 @@ func (r *VerticalFormatter) Ptr() *float64 { return &r.size.Y }
 func (r *VerticalFormatter) Sum(a mat.AABB) float64 { return fill.vSum(a) }
 
 func (r *VerticalFormatter) Provide(takable, taken float64) float64 {
-	return r.Module.Height(takable, taken)
+	return r.clamp(r.Module.Height(takable, taken), Y)
 }
 
 func (r *VerticalFormatter) MarginPtr() [2]*float64 {
@@ -370,7 +730,8 @@ type Formatter interface {
 	Ptr() *float64
 	// Sum sums up the margin/padding simension
 	Sum(mat.AABB) float64
-	// Provide calls method on elements module with given arguments
+	// Provide calls method on elements module with given arguments, result is clamped
+	// to MinSize and MaxSize
 	Provide(float64, float64) float64
 	// MarginPtr returns pointers to margin dimensions
 	MarginPtr() [2]*float64
@@ -384,7 +745,8 @@ type Formatter interface {
 // for Processor to process
 type Scene struct {
 	Redraw, Resize Notifier
-	Root           Element
+	Root           Element // BaseLayer, see Layer for others
+	layers         [layerCount - 1]Element
 	Assets         *Assets
 	Batch          ggl.Batch
 
@@ -394,6 +756,45 @@ type Scene struct {
 
 	ids    map[string]*Element
 	groups map[string][]*Element
+
+	focused, clicked *Element
+	tabOrder         []*Element
+
+	hovered []*Element
+	down    [key.MouseLast + 1]*Element
+
+	// TooltipDelay is how long mouse has to stay on element until its tooltip shows up
+	TooltipDelay      float64
+	tipTime           float64
+	tooltip, tipOwner *Element
+
+	// GhostMask is mask of ghost of dragged element
+	GhostMask mat.RGBA
+	drag      drag
+
+	relayout []*Element
+	drawGen  int
+	part     ggl.Data
+	partBase [2]int
+
+	scale      float64
+	media      mat.Vec
+	rules      []mediaRule
+	rulesValid bool
+
+	theme     []string
+	vars      goss.Style
+	varsValid bool
+
+	data     interface{}
+	bindings map[*Element][]*Binding
+
+	animations, stepping []*animation
+	appearing            []*Element
+
+	watcher   *load.Watcher
+	gossFiles []string
+	gomlFiles map[string][]*gomlSource
 }
 
 // NScene returns ready-to-use scene, do not use Scene{}
@@ -410,7 +811,9 @@ func NScene() *Scene {
 			Markdowns: map[string]*txt.Markdown{
 				"default": txt.NMarkdown(),
 			},
-			Cursors: map[string]CursorDrawer{},
+			Cursors:    map[string]CursorDrawer{},
+			Validators: map[string]Validator{},
+			Patterns:   map[string]string{},
 		},
 		Parser: NParser(),
 	}
@@ -419,11 +822,22 @@ func NScene() *Scene {
 		Pic: txt.Atlas7x13.Pic,
 	})
 
-	s.Root.init(s)
+	s.init()
 
 	return s
 }
 
+// init initializes layers and sets defaults
+func (s *Scene) init() {
+	s.TooltipDelay = DefaultTooltipDelay
+	s.GhostMask = DefaultGhostMask
+	s.scale = 1
+	s.Root.init(s)
+	for i := range s.layers {
+		s.layers[i].init(s)
+	}
+}
+
 // in comparison to NScene this function can me used before window creation
 func NEmptyScene() *Scene {
 	s := &Scene{
@@ -432,7 +846,7 @@ func NEmptyScene() *Scene {
 		Assets: &Assets{},
 	}
 
-	s.Root.init(s)
+	s.init()
 
 	return s
 }
@@ -447,6 +861,7 @@ func (s *Scene) LoadGoss(paths ...string) error {
 		if err != nil {
 			return err
 		}
+		s.addGossFile(p)
 	}
 
 	return nil
@@ -467,6 +882,7 @@ func (s *Scene) AddGoss(source []byte) error {
 	}
 
 	s.Assets.Styles.Add(stl)
+	s.rulesValid, s.varsValid = false, false
 	return nil
 }
 
@@ -536,14 +952,22 @@ func (s *Scene) InitStyle(e *Element) {
 		if ok {
 			style.Overwrite(e.Style)
 		}
+		s.applyRules(st, e.Style)
 	}
 	if e.Raw.Style != nil {
 		e.Raw.Style.Overwrite(e.Style)
 	}
+	s.resolveVars(e)
+	old := e.layout()
+	e.Props.scale = s.scale
 	e.Init()
 	if e.Parent != nil {
 		e.Inherit(e.Parent.Style)
 	}
+	if e.styled {
+		e.transitionStyle(old)
+	}
+	e.styled = true
 }
 
 // Notifier ...
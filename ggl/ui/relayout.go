@@ -0,0 +1,147 @@
+package ui
+
+import (
+	"github.com/jakubDoka/mlok/ggl"
+)
+
+// maxRelayouts limits how many times layout boundaries are relayouted in one update, modules
+// that relayout themselves on each frame change would loop forever otherwise
+const maxRelayouts = 4
+
+// drawRange is part of Scene.Batch element occupies
+type drawRange struct {
+	gen, vs, ve, is, ie int
+}
+
+// Relayout marks element as changed. Unlike Scene.Resize.Notify, only the closest layout
+// boundary containing the element is resized and redrawn on next update. Layout boundary
+// is element whose size cannot be changed by its children, it has size that is not fill
+// and resizing set to ignore:
+//
+//	counter_box{
+//		size: 100 20;
+//		resizing: ignore;
+//	}
+//
+// if there is no boundary, whole layer is resized. Text modules call this when their
+// content changes.
+func (e *Element) Relayout() {
+	if e.Scene == nil {
+		return
+	}
+	e.Scene.relayout = append(e.Scene.relayout, e)
+}
+
+// IsBoundary returns whether element is layout boundary, see Relayout
+func (e *Element) IsBoundary() bool {
+	return e.Size.X != Fill && e.Size.Y != Fill && e.Ingors(X) && e.Ingors(Y)
+}
+
+// boundary returns closest layout boundary that contains element or root of the layer
+func (e *Element) boundary() *Element {
+	for e.Parent != nil && !e.IsBoundary() {
+		e = e.Parent
+	}
+	return e
+}
+
+// visible returns whether element and all its parents are not hidden
+func (e *Element) visible() bool {
+	for ; e != nil; e = e.Parent {
+		if e.hidden {
+			return false
+		}
+	}
+	return true
+}
+
+// relayout resizes and redraws boundaries of elements marked by Relayout
+func (p *Processor) relayout() {
+	s := p.scene
+	p.bounds = p.bounds[:0]
+o:
+	for _, e := range s.relayout {
+		if e.Scene != s || !e.visible() {
+			continue
+		}
+		b := e.boundary()
+		for _, o := range p.bounds {
+			if o.isParentOf(b) {
+				continue o
+			}
+		}
+		// b can contain more boundaries that were already collected
+		n := 0
+		for _, o := range p.bounds {
+			if !b.isParentOf(o) {
+				p.bounds[n] = o
+				n++
+			}
+		}
+		p.bounds = append(p.bounds[:n], b)
+	}
+	s.relayout = s.relayout[:0]
+
+	for _, b := range p.bounds {
+		if b.Parent == nil {
+			p.resizeLayer(b)
+			s.Redraw.Notify()
+			continue
+		}
+
+		// boundary keeps its size so it also stays on its place
+		pos := b.Frame.Min.Sub(b.margin.Min).Sub(b.Offest)
+		p.resize(b, b.size.X, &p.horizontalFormatter, X)
+		p.resize(b, b.size.Y, &p.verticalFormatter, Y)
+		b.move(pos, false)
+	}
+
+	if s.Redraw.Should() {
+		return
+	}
+	for _, b := range p.bounds {
+		if !p.redrawPart(b) {
+			s.Redraw.Notify()
+			return
+		}
+	}
+}
+
+// redrawPart redraws element in place if it produces the same amount of triangles as
+// before and nothing modifies them, it returns false if full redraw is needed
+func (p *Processor) redrawPart(e *Element) bool {
+	s := p.scene
+	r := e.drawn
+	if r.gen != s.drawGen {
+		return false
+	}
+	for ch := e.Parent; ch != nil; ch = ch.Parent {
+		if ch.Proc != nil {
+			return false
+		}
+	}
+
+	s.part.Clear()
+	s.partBase = [2]int{r.vs, r.is}
+	e.redraw(&s.part, &p.canvas)
+	if len(s.part.Vertexes) != r.ve-r.vs || len(s.part.Indices) != r.ie-r.is {
+		return false
+	}
+
+	s.part.Indices.Shift(uint32(r.vs))
+	copy(s.Batch.Vertexes[r.vs:], s.part.Vertexes)
+	copy(s.Batch.Indices[r.is:], s.part.Indices)
+	return true
+}
+
+// drawData returns data t writes to and offset of data in Batch if element drawn into
+// t can be later redrawn in place
+func (s *Scene) drawData(t ggl.Target) (d *ggl.Data, v, i int) {
+	switch t {
+	case &s.Batch:
+		return &s.Batch.Data, 0, 0
+	case &s.part:
+		return &s.part, s.partBase[0], s.partBase[1]
+	}
+	return
+}
@@ -0,0 +1,214 @@
+package ui
+
+import (
+	"io/ioutil"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/jakubDoka/mlok/load"
+)
+
+// StateKeeper can be implemented by Module to keep its state when element is rebuilt by hot
+// reload, KeepState is called on new module with old module of the same type after new
+// element is initialized
+type StateKeeper interface {
+	KeepState(old Module)
+}
+
+// gomlSource remembers elements that were loaded from goml file
+type gomlSource struct {
+	parent *Element
+	elems  []*Element
+}
+
+// HotReload makes scene watch all goss and goml files loaded by Scene.LoadGoss and
+// Element.LoadGoml, files are checked at most once per period during Processor.Update.
+// When goss file changes, styles are reloaded with Scene.ReloadStyle. When goml file changes,
+// elements loaded from it are rebuilt in place and modules implementing StateKeeper keep
+// their state, so Area keeps its content and Scroll keeps its offset. Errors are reported
+// by Scene.Log. It is meant for development, calling it again just changes the period.
+//
+//	scene.HotReload(time.Second / 2)
+func (s *Scene) HotReload(period time.Duration) {
+	if s.watcher != nil {
+		s.watcher.Period = period
+		return
+	}
+
+	s.watcher = load.NWatcher(period)
+	for _, p := range s.gossFiles {
+		s.watchGoss(p)
+	}
+	for p := range s.gomlFiles {
+		s.watchGoml(p)
+	}
+}
+
+// Watcher returns watcher used for hot reload or nil if HotReload was not called, you can
+// use it to watch your own files, for example particle styles
+func (s *Scene) Watcher() *load.Watcher {
+	return s.watcher
+}
+
+// pollReload checks watched files
+func (s *Scene) pollReload() {
+	if s.watcher != nil {
+		s.Log(&s.Root, s.watcher.Poll())
+	}
+}
+
+// addGossFile remembers loaded goss file
+func (s *Scene) addGossFile(path string) {
+	for _, p := range s.gossFiles {
+		if p == path {
+			return
+		}
+	}
+	s.gossFiles = append(s.gossFiles, path)
+	if s.watcher != nil {
+		s.watchGoss(path)
+	}
+}
+
+// addGomlFile remembers elements loaded from goml file
+func (s *Scene) addGomlFile(path string, parent *Element, elems []*Element) {
+	if s.gomlFiles == nil {
+		s.gomlFiles = map[string][]*gomlSource{}
+	}
+	if _, ok := s.gomlFiles[path]; !ok && s.watcher != nil {
+		s.watchGoml(path)
+	}
+	s.gomlFiles[path] = append(s.gomlFiles[path], &gomlSource{parent, elems})
+}
+
+func (s *Scene) watchGoss(path string) {
+	s.watcher.Watch(path, func(path string) error {
+		bts, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if err := s.AddGoss(bts); err != nil {
+			return err
+		}
+		s.forLayers(s.ReloadStyle)
+		return nil
+	})
+}
+
+func (s *Scene) watchGoml(path string) {
+	s.watcher.Watch(path, func(path string) error {
+		bts, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for _, src := range s.gomlFiles[path] {
+			if err := s.reloadGoml(src, bts); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// reloadGoml replaces elements of source with new ones parsed from goml
+func (s *Scene) reloadGoml(src *gomlSource, source []byte) error {
+	elems, err := s.Parse(source)
+	if err != nil {
+		return err
+	}
+
+	old := map[*Element]bool{}
+	for _, e := range src.elems {
+		old[e] = true
+	}
+
+	index := src.parent.ChildCount()
+	for i := 0; i < src.parent.ChildCount(); i++ {
+		if old[src.parent.ChildAt(i)] {
+			index = i
+			break
+		}
+	}
+
+	focused := s.focused
+	byName := map[string]*Element{}
+	for _, e := range src.elems {
+		if e.Parent == src.parent {
+			src.parent.RemoveChild(e.name)
+		}
+		byName[e.name] = e
+	}
+
+	for i, e := range elems {
+		if e.noName {
+			e.name = strconv.Itoa(index + i)
+		}
+		src.parent.InsertChild(e.name, index+i, e)
+		if o, ok := byName[e.name]; ok {
+			s.keepState(o, e, focused)
+		}
+	}
+
+	src.elems = elems
+	return nil
+}
+
+// keepState transfers state from old element tree to new one, elements are paired by name
+func (s *Scene) keepState(old, e *Element, focused *Element) {
+	if k, ok := e.Module.(StateKeeper); ok && reflect.TypeOf(old.Module) == reflect.TypeOf(e.Module) {
+		k.KeepState(old.Module)
+	}
+	if e.context == nil && old.context != nil {
+		e.SetContext(old.context)
+	}
+	if old == focused && e.Focusable() {
+		s.Focus(e)
+	}
+
+	for i := 0; i < e.ChildCount(); i++ {
+		ch := e.ChildAt(i)
+		if o, ok := old.Child(ch.name); ok {
+			s.keepState(o, ch, focused)
+		}
+	}
+}
+
+// KeepState implements StateKeeper interface
+func (a *Area) KeepState(old Module) {
+	a.Content = append(a.Content[:0], old.(*Area).Content...)
+	a.Dirty()
+}
+
+// KeepState implements StateKeeper interface
+func (s *Scroll) KeepState(old Module) {
+	o := old.(*Scroll)
+	s.offset = o.offset
+	s.X.position, s.Y.position = o.X.position, o.Y.position
+	s.dirty = true
+}
+
+// KeepState implements StateKeeper interface
+func (c *Checkbox) KeepState(old Module) {
+	c.SetChecked(old.(*Checkbox).Checked)
+}
+
+// KeepState implements StateKeeper interface
+func (r *RadioGroup) KeepState(old Module) {
+	r.SetBindValue(old.(*RadioGroup).Selected)
+}
+
+// KeepState implements StateKeeper interface
+func (s *Slider) KeepState(old Module) {
+	s.SetBindValue(old.(*Slider).Value)
+}
+
+// KeepState implements StateKeeper interface
+func (d *Dropdown) KeepState(old Module) {
+	d.SetBindValue(old.(*Dropdown).Value())
+}
+
+// KeepState implements StateKeeper interface
+func (l *List) KeepState(old Module) {
+	l.SetSource(old.(*List).Source)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/jakubDoka/mlok/ggl/txt"
 	"github.com/jakubDoka/mlok/load"
 	"github.com/jakubDoka/mlok/mat"
+	"github.com/jakubDoka/mlok/mat/lerp"
 
 	"github.com/jakubDoka/goml/goss"
 )
@@ -19,6 +20,11 @@ type Assets struct {
 	Markdowns map[string]*txt.Markdown
 	// Cursors are avaliable cursor drawers
 	Cursors map[string]CursorDrawer
+	// Validators can be referenced from Area style by name
+	Validators map[string]Validator
+	// Patterns are regexes that can be referenced from Area style by name, goss cannot
+	// hold them directly
+	Patterns map[string]string
 	// styles should be supplied from .goss files
 	goss.Styles
 }
@@ -33,9 +39,33 @@ type Props struct {
 	Padding mat.AABB
 	// Size defines default size of element
 	Size mat.Vec
+	// Percent is size as fraction of space parent offers to its children, it overrides
+	// Size in dimensions where it is not zero
+	Percent mat.Vec
+	// MinSize and MaxSize clamp the size of element, MaxSize is unlimited by default
+	MinSize, MaxSize mat.Vec
+	// AspectRatio makes height of element equal to width divided by ratio if not zero
+	AspectRatio float64
 	// Composition defines orientation of children in div, if horizontal
 	// or vertical, if Composition.None() then it is initialized to be Vertical
 	Composition
+	// Alignment defines how children are aligned on cross axis of a line, it
+	// is used only with wrap compositions
+	Alignment Alignment
+	// Gap is space between children and between lines, it is used only with
+	// wrap and grid compositions
+	Gap mat.Vec
+	// Columns and Rows are tracks of grid composition, if children do not fit
+	// into declared tracks, Auto tracks are added
+	Columns, Rows []Track
+	// Cell is position of element in parents grid, negative value means element
+	// is placed to first free cell
+	Cell [2]int
+	// Span is amount of cells element takes in parents grid, minimum is 1
+	Span [2]int
+	// TabIndex makes element focusable if it is not negative, elements are
+	// ordered by it when navigating with tab
+	TabIndex int
 	// resize mode sets how element should react to size of children, see
 	// constants documentation, if ResizeMode.None() then it is initialized with Expand
 	Resizing [2]ResizeMode
@@ -46,11 +76,20 @@ type Props struct {
 	// offset will be applied as offset from position where element would end up
 	// if it were only element in the parent
 	Offest mat.Vec
+	// Transition makes changes of margin, padding, size, offset and background animated,
+	// it also animates showing and hiding of element
+	Transition Transition
+	// Modal and Dismissible take effect on direct children of layers, see Layer
+	Modal, Dismissible bool
+	// Draggable and DropTarget enable drag and drop, see DragEvent
+	Draggable, DropTarget bool
+
+	scale float64
 }
 
 // Horizontal reports whether style composition is horizontal
 func (s *Props) Horizontal() bool {
-	return s.Composition == Horizontal
+	return s.Composition == Horizontal || s.Composition == HorizontalWrap
 }
 
 func (s *Props) Expands(dim Dimension) bool {
@@ -65,12 +104,49 @@ func (s *Props) Ingors(dim Dimension) bool {
 	return s.Resizing[dim] == Ignore
 }
 
+// clamp clamps size to MinSize and MaxSize
+func (s *Props) clamp(size float64, dim Dimension) float64 {
+	return math.Max(math.Min(size, *at(&s.MaxSize, dim)), *at(&s.MinSize, dim))
+}
+
+// resolveSize computes Size from Percent, AspectRatio, MinSize and MaxSize, space is
+// space offered by parent
+func (e *Element) resolveSize(space float64, dim Dimension) {
+	size := at(&e.Size, dim)
+	if percent := *at(&e.Percent, dim); percent != 0 {
+		*size = space * percent
+	}
+	if dim == Y && e.AspectRatio != 0 {
+		*size = e.size.X/e.AspectRatio - fill.vSum(e.Padding)
+	}
+	if *size != Fill {
+		*size = e.clamp(*size, dim)
+	}
+}
+
 // Init initializes the style
 func (s *Props) Init() {
-	s.Margin = s.AABB("margin", s.Margin)
-	s.Padding = s.AABB("padding", s.Padding)
-	s.Size = s.Vec("size", s.Size)
+	s.Margin = s.scaledAABB("margin", s.Margin)
+	s.Padding = s.scaledAABB("padding", s.Padding)
+	s.Size, s.Percent = s.RawStyle.Size("size", s.Size)
+	if _, ok := s.Style["size"]; ok {
+		s.Size = mat.V(s.scaled(s.Size.X), s.scaled(s.Size.Y))
+	}
+	s.MinSize = s.scaledVec("min_size", mat.ZV)
+	s.MaxSize = s.scaledVec("max_size", mat.V(unknown, unknown))
+	s.AspectRatio = s.Float("aspect_ratio", 0)
 	s.Composition = s.RawStyle.Composition("composition")
+	s.Alignment = s.RawStyle.Alignment("alignment")
+	s.Gap = s.scaledVec("gap", mat.ZV)
+	s.Columns = s.scaledTracks("columns")
+	s.Rows = s.scaledTracks("rows")
+
+	cell := s.Vec("cell", mat.V(-1, -1))
+	s.Cell = [2]int{int(cell.X), int(cell.Y)}
+	span := s.Vec("span", mat.V(1, 1))
+	s.Span = [2]int{mat.Maxi(int(span.X), 1), mat.Maxi(int(span.Y), 1)}
+
+	s.TabIndex = s.Int("tab_index", -1)
 
 	s.Resizing[0] = s.ResizeMode("resizing_x")
 	s.Resizing[1] = s.ResizeMode("resizing_y")
@@ -81,7 +157,12 @@ func (s *Props) Init() {
 	}
 
 	s.Relative = s.Bool("relative", false)
-	s.Offest = s.Vec("offset", mat.ZV)
+	s.Offest = s.scaledVec("offset", mat.ZV)
+	s.Transition = s.RawStyle.Transition("transition")
+	s.Modal = s.Bool("modal", false)
+	s.Dismissible = s.Bool("dismissible", false)
+	s.Draggable = s.Bool("draggable", false)
+	s.DropTarget = s.Bool("drop_target", false)
 }
 
 type Dimension uint8
@@ -91,6 +172,14 @@ const (
 	Y
 )
 
+// at returns pointer to component of vector in given dimension
+func at(v *mat.Vec, dim Dimension) *float64 {
+	if dim == Y {
+		return &v.Y
+	}
+	return &v.X
+}
+
 // Composition ...
 type Composition uint8
 
@@ -99,19 +188,101 @@ func (c Composition) None() bool {
 	return c == 0
 }
 
+// Wraps reports whether composition is one of wrap compositions
+func (c Composition) Wraps() bool {
+	return c == HorizontalWrap || c == VerticalWrap
+}
+
 const (
 	// Vertical makes children ordered vertically
 	Vertical Composition = iota
 	// Horizontal makes children ordered horizontally
 	Horizontal
+	// HorizontalWrap orders children horizontally and when they do not fit
+	// they flow onto new row bellow
+	HorizontalWrap
+	// VerticalWrap orders children vertically and when they do not fit
+	// they flow onto new column to the right
+	VerticalWrap
+	// Grid places children into cells of grid defined by Props.Columns and Props.Rows
+	Grid
 )
 
 // Compositions maps each composition to its string representation
 var Compositions = map[string]Composition{
-	"vertical":   Vertical,
-	"horizontal": Horizontal,
+	"vertical":        Vertical,
+	"horizontal":      Horizontal,
+	"wrap":            HorizontalWrap,
+	"horizontal_wrap": HorizontalWrap,
+	"vertical_wrap":   VerticalWrap,
+	"grid":            Grid,
+}
+
+// Alignment is alignment of children on cross axis of a wrap line
+type Alignment uint8
+
+// Alignment constants
+const (
+	// AlignStart aligns children to the start of a line, top for rows and left for columns
+	AlignStart Alignment = iota
+	// AlignCenter centers children inside line
+	AlignCenter
+	// AlignEnd aligns children to the end of a line
+	AlignEnd
+	// AlignStretch stretches children so they take whole line
+	AlignStretch
+)
+
+// Alignments maps each alignment to its string representation
+var Alignments = map[string]Alignment{
+	"start":   AlignStart,
+	"center":  AlignCenter,
+	"end":     AlignEnd,
+	"stretch": AlignStretch,
+}
+
+// offset returns offset of item with given size inside the line, offset is always from
+// the line minimum, flip should be true if line starts at maximum
+func (a Alignment) offset(line, size float64, flip bool) float64 {
+	free := line - size
+	switch a {
+	case AlignCenter:
+		return free * .5
+	case AlignEnd:
+		if flip {
+			return 0
+		}
+		return free
+	default:
+		if flip {
+			return free
+		}
+		return 0
+	}
+}
+
+// Track is a column or row of grid composition
+type Track struct {
+	Mode TrackMode
+	// Value is size in pixels for TrackPixels and weight for TrackFill
+	Value float64
 }
 
+// TrackMode determinate how size of grid track is resolved
+type TrackMode uint8
+
+// TrackMode constants
+const (
+	// TrackAuto makes track as big as biggest child inside it, children spanning multiple
+	// tracks are not considered
+	TrackAuto TrackMode = iota
+	// TrackPixels makes track of fixed size
+	TrackPixels
+	// TrackFill makes track take remaining space, space is split between fill tracks
+	// by their weights
+	TrackFill
+)
+
 // ResizeMode ...
 type ResizeMode uint8
 
@@ -235,6 +406,134 @@ func (r RawStyle) Composition(key string) (c Composition) {
 	return
 }
 
+// Alignment parses alignment, if parsing fails AlignStart is returned
+func (r RawStyle) Alignment(key string) (a Alignment) {
+	val, ok := r.Style[key]
+	if !ok {
+		return
+	}
+
+	switch v := val[0].(type) {
+	case int:
+		return Alignment(v)
+	case string:
+		return Alignments[v]
+	}
+	return
+}
+
+// Tracks parses grid tracks, number is fixed size, "auto" makes track fit the content, "fill"
+// takes remaining space and "fr" followed by number does the same but number specifies weight
+// ("fill" equals "fr 1"), invalid values are skipped
+//
+//	columns: 100 auto fill fr 2; // four tracks
+func (r RawStyle) Tracks(key string) (t []Track) {
+	val, ok := r.Style[key]
+	if !ok {
+		return
+	}
+
+	for i := 0; i < len(val); i++ {
+		switch v := val[i].(type) {
+		case int:
+			t = append(t, Track{TrackPixels, float64(v)})
+		case float64:
+			t = append(t, Track{TrackPixels, v})
+		case string:
+			switch v {
+			case "auto":
+				t = append(t, Track{Mode: TrackAuto})
+			case "fill":
+				t = append(t, Track{TrackFill, 1})
+			case "fr":
+				var weight [1]float64
+				if i+1 < len(val) && load.CollectFloats(val[i+1:i+2], weight[:]) == 1 {
+					t = append(t, Track{TrackFill, weight[0]})
+					i++
+				}
+			}
+		}
+	}
+
+	return
+}
+
+// Transition parses the transition, it can be just a duration, duration and easing name
+// or duration followed by four control points of bezier curve
+//
+//	transition: .3;
+//	transition: .3 ease_out;
+//	transition: .3 0 1 .8 1;
+func (r RawStyle) Transition(key string) (t Transition) {
+	t.Easing = Easings["linear"]
+	val, ok := r.Style[key]
+	if !ok {
+		return
+	}
+
+	t.Duration = r.Float(key, 0)
+
+	var points [4]float64
+	switch {
+	case len(val) == 2:
+		if name, ok := val[1].(string); ok {
+			if e, ok := Easings[name]; ok {
+				t.Easing = e
+			}
+		}
+	case len(val) == 5 && load.CollectFloats(val[1:], points[:]) == 4:
+		t.Easing = lerp.Bezier(points[0], points[1], points[2], points[3])
+	}
+
+	return
+}
+
+// Size parses size, components can be numbers, fill or percentages of space parent offers
+// marked by percent keyword after number, percent is returned as fraction and it is zero for
+// components that are not percentages, if parsing fails, def is returned
+//
+//	size: 50 percent fill;
+//	size: 100 25 percent;
+func (r RawStyle) Size(key string, def mat.Vec) (size, percent mat.Vec) {
+	size = def
+	val, ok := r.Style[key]
+	if !ok {
+		return
+	}
+
+	var s, p [2]float64
+	n := 0
+	for i := 0; i < len(val) && n < 2; i++ {
+		switch v := val[i].(type) {
+		case int:
+			s[n] = float64(v)
+		case float64:
+			s[n] = v
+		case string:
+			if v != "fill" {
+				return
+			}
+			s[n] = Fill
+		default:
+			return
+		}
+		if i+1 < len(val) && val[i+1] == "percent" && s[n] != Fill {
+			p[n], s[n] = s[n]/100, 0
+			i++
+		}
+		n++
+	}
+
+	switch n {
+	case 1:
+		return mat.V(s[0], s[0]), mat.V(p[0], p[0])
+	case 2:
+		return mat.V(s[0], s[1]), mat.V(p[0], p[1])
+	}
+
+	return def, mat.ZV
+}
+
 // ResizeMode parser resize mode, if pasring fails Expand is returned
 func (r RawStyle) ResizeMode(key string) (e ResizeMode) {
 	val, ok := r.Style[key]
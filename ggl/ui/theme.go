@@ -0,0 +1,130 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/jakubDoka/goml/goss"
+	"github.com/jakubDoka/sterr"
+)
+
+// theme errors
+var (
+	ErrMissingTheme    = sterr.New("theme with name '%s' is not present in styles")
+	ErrMissingVariable = sterr.New("variable '%s' is not defined")
+)
+
+// Variables is name of style that holds variables used by all themes
+const Variables = "variables"
+
+// VarPrefix marks identifier in style as variable reference
+const VarPrefix = "var_"
+
+// SetTheme changes the variable set of styles and reloads style of all elements. Properties
+// of style named Variables are variables that can be referenced from any style by VarPrefix,
+// themes are styles that overwrite the variables in given order:
+//
+//	variables{
+//		pad: 4;
+//		accent: blue;
+//	}
+//	dark{ background: .1; text: white; }
+//	light{ background: .9; text: black; }
+//	colorblind{ accent: orange; }
+//
+//	button{
+//		padding: var_pad;
+//		background: var_background;
+//		text_color: var_text;
+//		hover_mask: var_accent;
+//	}
+//
+//	err := scene.SetTheme("dark", "colorblind")
+//
+// Variable can hold multiple values, they are all inserted in place of reference. Referenced
+// variable that does not exist is reported by Scene.Log.
+func (s *Scene) SetTheme(names ...string) error {
+	for _, n := range names {
+		if _, ok := s.Assets.Styles[n]; !ok {
+			return ErrMissingTheme.Args(n)
+		}
+	}
+
+	s.theme = append(s.theme[:0], names...)
+	s.varsValid = false
+	s.forLayers(s.ReloadStyle)
+	return nil
+}
+
+// Theme returns names of current themes
+func (s *Scene) Theme() []string {
+	return s.theme
+}
+
+// Variable returns value of variable in current theme
+func (s *Scene) Variable(name string) ([]interface{}, bool) {
+	s.updateVars()
+	v, ok := s.vars[name]
+	return v, ok
+}
+
+// updateVars collects variables from assets if they were invalidated
+func (s *Scene) updateVars() {
+	if s.varsValid {
+		return
+	}
+	s.varsValid = true
+
+	s.vars = goss.Style{}
+	if st, ok := s.Assets.Styles[Variables]; ok {
+		st.Overwrite(s.vars)
+	}
+	for _, n := range s.theme {
+		if st, ok := s.Assets.Styles[n]; ok {
+			st.Overwrite(s.vars)
+		}
+	}
+}
+
+// resolveVars replaces variable references in style of element, values are copied as
+// they are shared with styles in assets
+func (s *Scene) resolveVars(e *Element) {
+	s.updateVars()
+	for k, v := range e.Style {
+		if !hasVar(v) {
+			continue
+		}
+
+		nv := make([]interface{}, 0, len(v))
+		for _, val := range v {
+			name, ok := val.(string)
+			if !ok || !strings.HasPrefix(name, VarPrefix) {
+				nv = append(nv, val)
+				continue
+			}
+
+			name = name[len(VarPrefix):]
+			vr, ok := s.vars[name]
+			if !ok {
+				s.Log(e, ErrMissingVariable.Args(name))
+				continue
+			}
+			nv = append(nv, vr...)
+		}
+
+		if len(nv) == 0 {
+			delete(e.Style, k)
+		} else {
+			e.Style[k] = nv
+		}
+	}
+}
+
+// hasVar returns whether values contain variable reference
+func hasVar(values []interface{}) bool {
+	for _, v := range values {
+		if name, ok := v.(string); ok && strings.HasPrefix(name, VarPrefix) {
+			return true
+		}
+	}
+	return false
+}
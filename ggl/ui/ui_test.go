@@ -1,10 +1,24 @@
 package ui
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strconv"
+	"strings"
 	"testing"
 
+	inp "github.com/jakubDoka/mlok/ggl/input"
+	"github.com/jakubDoka/mlok/ggl/key"
+	"github.com/jakubDoka/mlok/ggl/txt"
+	"github.com/jakubDoka/mlok/mat"
+
 	"github.com/jakubDoka/goml"
+	"github.com/jakubDoka/goml/goss"
 	"github.com/jakubDoka/sterr"
 )
 
@@ -367,3 +381,1784 @@ func TestInsertChild(t *testing.T) {
 		t.Error(ch.Index(), ch4.Index(), ch2.Index(), ch3.Index(), ch5.Index())
 	}
 }
+
+func TestWrap(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		container goss.Style
+		children  []mat.Vec
+		frame     mat.AABB
+		res       []mat.AABB
+	}{
+		{
+			desc: "rows",
+			container: goss.Style{
+				"size":        {100, 0},
+				"composition": {"wrap"},
+				"gap":         {10},
+				"alignment":   {"center"},
+			},
+			children: []mat.Vec{{X: 40, Y: 20}, {X: 40, Y: 30}, {X: 40, Y: 20}, {X: 40, Y: 10}},
+			frame:    mat.A(0, 0, 100, 60),
+			res: []mat.AABB{
+				mat.A(0, 35, 40, 55),
+				mat.A(50, 30, 90, 60),
+				mat.A(0, 0, 40, 20),
+				mat.A(50, 5, 90, 15),
+			},
+		},
+		{
+			desc: "stretch",
+			container: goss.Style{
+				"size":        {100, 0},
+				"composition": {"horizontal_wrap"},
+				"gap":         {10},
+				"alignment":   {"stretch"},
+			},
+			children: []mat.Vec{{X: 40, Y: 20}, {X: 40, Y: 30}, {X: 40, Y: 20}, {X: 40, Y: 10}},
+			frame:    mat.A(0, 0, 100, 60),
+			res: []mat.AABB{
+				mat.A(0, 30, 40, 60),
+				mat.A(50, 30, 90, 60),
+				mat.A(0, 0, 40, 20),
+				mat.A(50, 0, 90, 20),
+			},
+		},
+		{
+			desc: "columns",
+			container: goss.Style{
+				"size":        {0, 100},
+				"composition": {"vertical_wrap"},
+				"gap":         {10},
+			},
+			children: []mat.Vec{{X: 20, Y: 40}, {X: 30, Y: 40}, {X: 20, Y: 40}},
+			frame:    mat.A(0, 0, 60, 100),
+			res: []mat.AABB{
+				mat.A(0, 60, 20, 100),
+				mat.A(0, 10, 30, 50),
+				mat.A(40, 60, 60, 100),
+			},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			s := NEmptyScene()
+			c := NElement()
+			c.Raw.Style = tC.container
+			for i, v := range tC.children {
+				ch := NElement()
+				ch.Raw.Style = goss.Style{"size": {v.X, v.Y}}
+				c.AddChild(strconv.Itoa(i), ch)
+			}
+			s.Root.AddChild("c", c)
+
+			var p Processor
+			p.SetScene(s)
+			p.SetFrame(mat.A(0, 0, 200, 200))
+			p.Resize()
+
+			if c.Frame != tC.frame {
+				t.Errorf("\n%v\n%v", c.Frame, tC.frame)
+			}
+			for i, r := range tC.res {
+				if f := c.ChildAt(i).Frame; f != r {
+					t.Errorf("\n%d %v\n%v", i, f, r)
+				}
+			}
+		})
+	}
+}
+
+func TestGrid(t *testing.T) {
+	s := NEmptyScene()
+	c := NElement()
+	c.Raw.Style = goss.Style{
+		"size":        {200, 0},
+		"composition": {"grid"},
+		"columns":     {50, "fill", "auto"},
+		"rows":        {20, "auto"},
+		"gap":         {10},
+	}
+	children := []goss.Style{
+		{"size": {30, 10}},
+		{"size": {"fill", 10}},
+		{"size": {40, 30}},
+		{"size": {"fill", 25}, "cell": {0, 1}, "span": {2, 1}},
+	}
+	for i, st := range children {
+		ch := NElement()
+		ch.Raw.Style = st
+		c.AddChild(strconv.Itoa(i), ch)
+	}
+	s.Root.AddChild("c", c)
+
+	var p Processor
+	p.SetScene(s)
+	p.SetFrame(mat.A(0, 0, 300, 300))
+	p.Resize()
+
+	if r := mat.A(0, 0, 200, 55); c.Frame != r {
+		t.Errorf("\n%v\n%v", c.Frame, r)
+	}
+
+	res := []mat.AABB{
+		mat.A(0, 45, 30, 55),
+		mat.A(60, 45, 150, 55),
+		mat.A(160, 25, 200, 55),
+		mat.A(0, 0, 150, 25),
+	}
+	for i, r := range res {
+		if f := c.ChildAt(i).Frame; f != r {
+			t.Errorf("\n%d %v\n%v", i, f, r)
+		}
+	}
+}
+
+func TestFocus(t *testing.T) {
+	s := NEmptyScene()
+	var log []string
+	elems := map[string]*Element{}
+	for _, v := range []struct {
+		name  string
+		index int
+		frame mat.AABB
+	}{
+		{"a", 1, mat.A(0, 0, 10, 10)},
+		{"b", 0, mat.A(20, 0, 30, 10)},
+		{"c", -1, mat.A(40, 0, 50, 10)},
+		{"d", 1, mat.A(0, 20, 10, 30)},
+	} {
+		name := v.name
+		e := NElement()
+		e.Raw.Style = goss.Style{"tab_index": {v.index}}
+		e.Listen(Focus, func(interface{}) { log = append(log, "focus "+name) })
+		e.Listen(Blur, func(interface{}) { log = append(log, "blur "+name) })
+		s.Root.AddChild(name, e)
+		e.Frame = v.frame
+		elems[name] = e
+	}
+
+	testCases := []struct {
+		desc    string
+		do      func()
+		focused string
+		log     []string
+	}{
+		{
+			desc:    "first",
+			do:      func() { s.FocusNext(false) },
+			focused: "b",
+			log:     []string{"focus b"},
+		},
+		{
+			desc:    "next",
+			do:      func() { s.FocusNext(false) },
+			focused: "a",
+			log:     []string{"blur b", "focus a"},
+		},
+		{
+			desc:    "up",
+			do:      func() { s.FocusDirection(mat.V(0, 1)) },
+			focused: "d",
+			log:     []string{"blur a", "focus d"},
+		},
+		{
+			desc:    "wrap",
+			do:      func() { s.FocusNext(false) },
+			focused: "b",
+			log:     []string{"blur d", "focus b"},
+		},
+		{
+			desc:    "reverse wrap",
+			do:      func() { s.FocusNext(true) },
+			focused: "d",
+			log:     []string{"blur b", "focus d"},
+		},
+		{
+			desc:    "hide",
+			do:      func() { elems["d"].SetHidden(true) },
+			focused: "",
+			log:     []string{"blur d"},
+		},
+		{
+			desc: "arrows without focus",
+			do: func() {
+				var f inp.Fake
+				f.Press(key.Up)
+				f.Press(key.Right)
+				f.Update()
+				s.updateFocus(&f)
+			},
+			focused: "",
+			log:     []string{},
+		},
+		{
+			desc:    "nothing in direction",
+			do:      func() { s.Focus(elems["a"]); s.FocusDirection(mat.V(-1, 0)) },
+			focused: "a",
+			log:     []string{"focus a"},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			log = log[:0]
+			tC.do()
+			var name string
+			if f := s.Focused(); f != nil {
+				name = f.Name()
+			}
+			if name != tC.focused || !reflect.DeepEqual(log, tC.log) {
+				t.Errorf("\n%v %v\n%v %v", name, log, tC.focused, tC.log)
+			}
+		})
+	}
+}
+
+func TestRadioGroup(t *testing.T) {
+	s := NEmptyScene()
+	s.Assets.Markdowns = map[string]*txt.Markdown{"default": txt.NMarkdown()}
+	group := NElement()
+	group.Module = &RadioGroup{}
+	for _, name := range []string{"a", "b", "c"} {
+		ch := NElement()
+		ch.Module = &Checkbox{}
+		if name == "b" {
+			ch.Raw.Style = goss.Style{"checked": {true}}
+		}
+		group.AddChild(name, ch)
+	}
+	s.Root.AddChild("group", group)
+
+	var changes []interface{}
+	group.Listen(Changed, func(i interface{}) { changes = append(changes, i) })
+	r := group.Module.(*RadioGroup)
+
+	testCases := []struct {
+		desc     string
+		do       func()
+		selected string
+		checked  [3]bool
+		changes  int
+	}{
+		{
+			desc:     "initial",
+			do:       func() {},
+			selected: "b",
+			checked:  [3]bool{false, true, false},
+		},
+		{
+			desc: "click",
+			do: func() {
+				c, _ := group.Child("c")
+				c.Module.(*Checkbox).click()
+			},
+			selected: "c",
+			checked:  [3]bool{false, false, true},
+			changes:  1,
+		},
+		{
+			desc: "click selected",
+			do: func() {
+				c, _ := group.Child("c")
+				c.Module.(*Checkbox).click()
+			},
+			selected: "c",
+			checked:  [3]bool{false, false, true},
+			changes:  1,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			tC.do()
+			if r.Selected != tC.selected {
+				t.Errorf("\n%v\n%v", r.Selected, tC.selected)
+			}
+			for i, name := range []string{"a", "b", "c"} {
+				ch, _ := group.Child(name)
+				if c := ch.Module.(*Checkbox).Checked; c != tC.checked[i] {
+					t.Errorf("\n%s %v\n%v", name, c, tC.checked[i])
+				}
+			}
+			if len(changes) != tC.changes {
+				t.Errorf("\n%v\n%v", changes, tC.changes)
+			}
+		})
+	}
+}
+
+func TestSliderClamp(t *testing.T) {
+	testCases := []struct {
+		desc                    string
+		min, max, step, in, out float64
+	}{
+		{"inside", 0, 1, 0, .3, .3},
+		{"under", 0, 1, 0, -2, 0},
+		{"over", 0, 1, 0, 2, 1},
+		{"step", 0, 10, 2, 4.9, 4},
+		{"offset step", 1, 10, 2, 4.1, 5},
+		{"reversed", 1, 0, 0, 2, 1},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			s := Slider{Min: tC.min, Max: tC.max, Step: tC.step}
+			if v := s.clamp(tC.in); v != tC.out {
+				t.Errorf("\n%v\n%v", v, tC.out)
+			}
+		})
+	}
+}
+
+func TestBind(t *testing.T) {
+	type Player struct {
+		Name string
+		Dead bool
+	}
+	state := struct {
+		Player Player
+		Volume int
+	}{Player{Name: "bob"}, 5}
+
+	s := NEmptyScene()
+	s.Assets.Markdowns = map[string]*txt.Markdown{"default": txt.NMarkdown()}
+
+	area := NElement()
+	area.Module = &Area{}
+	area.Raw.Attributes = goml.Attribs{"bind": {"Player.Name"}, "bind_hidden": {"Player.Dead"}}
+	s.Root.AddChild("area", area)
+
+	slider := NElement()
+	slider.Module = &Slider{}
+	slider.Raw.Style = goss.Style{"slider_max": {10}, "slider_step": {1}}
+	slider.Raw.Attributes = goml.Attribs{"bind": {"Volume"}}
+	s.Root.AddChild("slider", slider)
+
+	a, sl := area.Module.(*Area), slider.Module.(*Slider)
+
+	testCases := []struct {
+		desc   string
+		do     func()
+		name   string
+		volume float64
+		hidden bool
+	}{
+		{
+			desc:   "bind",
+			do:     func() { s.Bind(&state) },
+			name:   "bob",
+			volume: 5,
+		},
+		{
+			desc: "data change",
+			do: func() {
+				state.Player.Name = "alice"
+				state.Player.Dead = true
+				state.Volume = 20
+				s.Sync()
+			},
+			name:   "alice",
+			volume: 10,
+			hidden: true,
+		},
+		{
+			desc: "edit",
+			do: func() {
+				a.SetText("eve")
+				area.Events.Invoke(TextChanged, "")
+				sl.SetValue(3)
+			},
+			name:   "eve",
+			volume: 3,
+			hidden: true,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			tC.do()
+			if v := string(a.Content); v != tC.name || state.Player.Name != tC.name {
+				t.Errorf("\n%v %v\n%v", v, state.Player.Name, tC.name)
+			}
+			if sl.Value != tC.volume {
+				t.Errorf("\n%v\n%v", sl.Value, tC.volume)
+			}
+			if area.Hidden() != tC.hidden {
+				t.Errorf("\n%v\n%v", area.Hidden(), tC.hidden)
+			}
+		})
+	}
+
+	if state.Volume != 3 {
+		t.Errorf("\n%v\n%v", state.Volume, 3)
+	}
+}
+
+func TestBindFailure(t *testing.T) {
+	type Player struct{ Name string }
+	state := struct{ Player *Player }{}
+
+	s := NEmptyScene()
+	s.Assets.Markdowns = map[string]*txt.Markdown{"default": txt.NMarkdown()}
+
+	var logged int
+	s.Root.Listen(Error, func(interface{}) { logged++ })
+
+	text := NElement()
+	text.Module = &Text{}
+	text.Raw.Attributes = goml.Attribs{"bind": {"Player.Name"}}
+	s.Root.AddChild("text", text)
+
+	var p Processor
+	p.SetScene(s)
+	p.SetFrame(mat.A(0, 0, 100, 100))
+
+	var f inp.Fake
+	f.Update()
+	testCases := []struct {
+		desc   string
+		do     func()
+		logged int
+		name   string
+	}{
+		{"nil pointer", func() { s.Bind(&state) }, 1, ""},
+		{"reported once", func() {}, 1, ""},
+		{"loaded", func() { state.Player = &Player{"bob"}; s.Bind(&state) }, 1, "bob"},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			tC.do()
+			p.Update(&f, .01)
+			p.Update(&f, .01)
+			if c := string(text.Module.(*Text).Content); logged != tC.logged || c != tC.name {
+				t.Errorf("\n%v %q\n%v %q", logged, c, tC.logged, tC.name)
+			}
+		})
+	}
+}
+
+func TestList(t *testing.T) {
+	type Item struct{ Name string }
+	items := make([]Item, 1000)
+	for i := range items {
+		items[i].Name = strconv.Itoa(i)
+	}
+
+	s := NEmptyScene()
+	s.Parser = NParser()
+	s.Assets.Markdowns = map[string]*txt.Markdown{"default": txt.NMarkdown()}
+
+	scroll := NElement()
+	scroll.Module = &Scroll{}
+	scroll.Raw.Style = goss.Style{"size": {100, 50}, "resizing_y": {"ignore"}}
+	s.Root.AddChild("scroll", scroll)
+
+	list := NElement()
+	l := &List{Template: goml.Element{Name: "text", Attributes: goml.Attribs{"bind": {"Name"}}}}
+	list.Module = l
+	list.Raw.Style = goss.Style{"item_size": {10}}
+	scroll.AddChild("list", list)
+	l.SetSlice(&items)
+
+	var p Processor
+	p.SetScene(s)
+	p.SetFrame(mat.A(0, 0, 100, 100))
+
+	testCases := []struct {
+		desc        string
+		offset      float64
+		first, last int
+	}{
+		{"top", 0, 0, 5},
+		{"scrolled", 205, 20, 26},
+		{"bottom", 9950, 995, 1000},
+		{"back", 0, 0, 5},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			// scroll normally aligns top of the list with its top
+			list.Offest.Y = 50 - 10000 + tC.offset
+			p.Resize()
+			if l.first != tC.first || l.last != tC.last {
+				t.Errorf("\n%v %v\n%v %v", l.first, l.last, tC.first, tC.last)
+			}
+			for i := tC.first; i < tC.last; i++ {
+				row := l.Row(i)
+				if c := string(row.Module.(*Text).Content); c != items[i].Name {
+					t.Errorf("\n%v\n%v", c, items[i].Name)
+				}
+				bottom := list.Frame.Max.Y - float64(i*10+10)
+				if b := row.Frame.Min.Y - row.margin.Min.Y; b != bottom {
+					t.Errorf("\n%v\n%v", b, bottom)
+				}
+			}
+			if list.ChildCount() > 6 {
+				t.Error(list.ChildCount())
+			}
+			if h := list.Frame.H(); h != 10000 {
+				t.Error(h)
+			}
+		})
+	}
+}
+
+func TestListChanges(t *testing.T) {
+	type Item struct{ Name string }
+	var items []Item
+	add := func(n int) {
+		for i := 0; i < n; i++ {
+			items = append(items, Item{strconv.Itoa(len(items))})
+		}
+	}
+
+	s := NEmptyScene()
+	s.Parser = NParser()
+	s.Assets.Markdowns = map[string]*txt.Markdown{"default": txt.NMarkdown()}
+
+	scroll := NElement()
+	scroll.Module = &Scroll{}
+	scroll.Raw.Style = goss.Style{"size": {100, 50}, "resizing_y": {"ignore"}}
+	s.Root.AddChild("scroll", scroll)
+
+	list := NElement()
+	l := &List{Template: goml.Element{Name: "text", Attributes: goml.Attribs{"bind": {"Name"}}}}
+	list.Module = l
+	list.Raw.Style = goss.Style{"item_size": {10}}
+	scroll.AddChild("list", list)
+	l.SetSlice(&items)
+
+	var p Processor
+	p.SetScene(s)
+	p.SetFrame(mat.A(0, 0, 100, 100))
+
+	var f inp.Fake
+	f.Update()
+	p.Update(&f, .01)
+
+	created := map[*Element]bool{}
+	testCases := []struct {
+		desc   string
+		change func()
+		shown  int
+	}{
+		{"append", func() { add(3) }, 3},
+		{"append over view", func() { add(5) }, 5},
+		{"shrink", func() { items = items[:2] }, 2},
+		{"edit", func() { items[0].Name = "edited"; l.Refresh() }, 2},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			tC.change()
+			p.Update(&f, .01)
+
+			if l.last-l.first != tC.shown {
+				t.Errorf("\n%v %v\n%v", l.first, l.last, tC.shown)
+			}
+			for i := l.first; i < l.last; i++ {
+				row := l.Row(i)
+				if c := string(row.Module.(*Text).Content); c != items[i].Name {
+					t.Errorf("\n%v\n%v", c, items[i].Name)
+				}
+				bottom := list.Frame.Max.Y - float64(i*10+10)
+				if b := row.Frame.Min.Y - row.margin.Min.Y; b != bottom || row.Frame.H() == 0 {
+					t.Errorf("\n%v\n%v", row.Frame, bottom)
+				}
+			}
+
+			// rows are recycled instead of recreated
+			for r := range created {
+				if r.Parent != list {
+					t.Error("row was removed")
+				}
+			}
+			for _, r := range l.rows {
+				created[r] = true
+			}
+			if list.ChildCount() > 6 {
+				t.Error(list.ChildCount())
+			}
+		})
+	}
+}
+
+func TestRemoveAnimated(t *testing.T) {
+	s := NEmptyScene()
+	e := NElement()
+	e.Raw.Style = goss.Style{"size": {10, 10}, "transition": {1}}
+	ch := NElement()
+	ch.Raw.Style = goss.Style{"size": {5, 5}, "transition": {1}}
+	e.AddChild("ch", ch)
+	s.Root.AddChild("e", e)
+
+	var p Processor
+	p.SetScene(s)
+	p.SetFrame(mat.A(0, 0, 100, 100))
+	p.Resize()
+
+	e.SetHidden(true)
+	ch.SetHidden(true)
+	if !s.Animating() {
+		t.Fatal("no animation started")
+	}
+	s.Root.RemoveChild("e")
+	if s.Animating() {
+		t.Error("animations of removed elements are still running")
+	}
+
+	var f inp.Fake
+	f.Update()
+	p.Update(&f, .1)
+}
+
+func TestTransition(t *testing.T) {
+	s := NEmptyScene()
+	e := NElement()
+	e.Raw.Style = goss.Style{"size": {10, 10}, "transition": {.5, "linear"}, "background": {0, 0, 0, 0}}
+	s.Root.AddChild("e", e)
+
+	var p Processor
+	p.SetScene(s)
+	p.SetFrame(mat.A(0, 0, 100, 100))
+	p.Resize()
+
+	testCases := []struct {
+		desc       string
+		do         func()
+		delta      float64
+		size       mat.Vec
+		background mat.RGBA
+		hidden     bool
+	}{
+		{
+			desc: "style",
+			do: func() {
+				e.Raw.Style["size"] = []interface{}{20, 30}
+				e.Raw.Style["background"] = []interface{}{1, 1, 1, 1}
+				s.ReloadStyle(e)
+			},
+			delta:      .25,
+			size:       mat.V(15, 20),
+			background: mat.RGBA{R: .5, G: .5, B: .5, A: .5},
+		},
+		{
+			desc:       "style end",
+			do:         func() {},
+			delta:      .5,
+			size:       mat.V(20, 30),
+			background: mat.White,
+		},
+		{
+			desc:       "hide",
+			do:         func() { e.SetHidden(true) },
+			delta:      .25,
+			size:       mat.V(10, 15),
+			background: mat.White,
+			hidden:     true,
+		},
+		{
+			desc:       "hide end",
+			do:         func() {},
+			delta:      .5,
+			size:       mat.V(20, 30),
+			background: mat.White,
+			hidden:     true,
+		},
+		{
+			desc: "show",
+			do: func() {
+				e.SetHidden(false)
+				p.Resize()
+				for _, e := range s.appearing {
+					e.appear()
+				}
+				s.appearing = s.appearing[:0]
+			},
+			delta:      .25,
+			size:       mat.V(10, 15),
+			background: mat.White,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			tC.do()
+			s.animate(tC.delta)
+			p.Resize()
+			if e.Size != tC.size {
+				t.Errorf("\n%v\n%v", e.Size, tC.size)
+			}
+			if bg := e.Module.(*ModuleBase).Background; bg != tC.background {
+				t.Errorf("\n%v\n%v", bg, tC.background)
+			}
+			if e.Hidden() != tC.hidden {
+				t.Errorf("\n%v\n%v", e.Hidden(), tC.hidden)
+			}
+		})
+	}
+}
+
+func TestHotReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scene.goml")
+	write := func(source string) {
+		if err := ioutil.WriteFile(path, []byte(source), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := NEmptyScene()
+	s.Parser = NParser()
+	s.Assets.Markdowns = map[string]*txt.Markdown{"default": txt.NMarkdown()}
+
+	write(`<div name="box"><area name="input"/></><div name="other"/>`)
+	if err := s.Root.LoadGoml(path); err != nil {
+		t.Fatal(err)
+	}
+	s.HotReload(0)
+
+	input, _ := s.Root.Child("box.input")
+	input.Module.(*Area).SetText("hello")
+	s.Focus(input)
+
+	write(`<div name="box"><text name="label"/><area name="input"/></><div name="other"/>`)
+	if err := s.watcher.Check(); err != nil {
+		t.Fatal(err)
+	}
+
+	box, ok := s.Root.Child("box")
+	if !ok || box.index != 0 {
+		t.Fatal(ok, box)
+	}
+	if _, ok := box.Child("label"); !ok {
+		t.Error("label is missing")
+	}
+
+	newInput, _ := box.Child("input")
+	if newInput == input {
+		t.Error("element was not rebuilt")
+	}
+	if c := string(newInput.Module.(*Area).Content); c != "hello" {
+		t.Errorf("\n%v\n%v", c, "hello")
+	}
+	if !newInput.Focused() {
+		t.Error("focus was lost")
+	}
+	if s.Root.ChildCount() != 2 {
+		t.Error(s.Root.ChildCount())
+	}
+}
+
+func TestInput(t *testing.T) {
+	s := NEmptyScene()
+	s.Assets.Markdowns = map[string]*txt.Markdown{"default": txt.NMarkdown()}
+	s.Parser = NParser()
+	if err := s.Root.AddGoml([]byte(`<checkbox name="check" style="size: 50;"/><area name="input" style="size: 50;"/>`)); err != nil {
+		t.Fatal(err)
+	}
+
+	var p Processor
+	p.SetScene(s)
+	p.SetFrame(mat.A(0, 0, 100, 100))
+	p.Resize()
+
+	check, _ := s.Root.Child("check")
+	input, _ := s.Root.Child("input")
+
+	var f inp.Fake
+	frame := func(do func()) {
+		do()
+		f.Update()
+		p.Update(&f, .1)
+	}
+
+	frame(func() { f.Move(check.Frame.Center()) })
+	frame(func() { f.Press(key.MouseLeft) })
+	frame(func() { f.Release(key.MouseLeft) })
+
+	if !check.Module.(*Checkbox).Checked {
+		t.Error("checkbox was not clicked")
+	}
+
+	frame(func() { f.Move(input.Frame.Center()) })
+	frame(func() { f.Press(key.MouseLeft) })
+	frame(func() { f.Release(key.MouseLeft) })
+	frame(func() { f.Type("hello") })
+
+	if s.Focused() != input || string(input.Module.(*Area).Content) != "hello" {
+		t.Errorf("\n%v\n%v", s.Focused() == input, string(input.Module.(*Area).Content))
+	}
+}
+
+func TestAreaEdit(t *testing.T) {
+	s := NEmptyScene()
+	s.Assets.Markdowns = map[string]*txt.Markdown{"default": txt.NMarkdown()}
+	s.Parser = NParser()
+	if err := s.Root.AddGoml([]byte(`<area name="input" style="size: 100 50;"/>`)); err != nil {
+		t.Fatal(err)
+	}
+
+	var p Processor
+	p.SetScene(s)
+	p.SetFrame(mat.A(0, 0, 100, 100))
+	p.Resize()
+
+	e, _ := s.Root.Child("input")
+	a := e.Module.(*Area)
+	s.Focus(e)
+
+	var f inp.Fake
+	frame := func(do func()) {
+		do()
+		f.Update()
+		p.Update(&f, .01)
+	}
+	press := func(keys ...key.Key) func() {
+		return func() {
+			frame(func() { f.Press(keys...) })
+			frame(func() { f.Release(keys...) })
+		}
+	}
+	typ := func(text string) func() {
+		return func() { frame(func() { f.Type(text) }) }
+	}
+
+	testCases := []struct {
+		desc       string
+		do         []func()
+		content    string
+		start, end int
+	}{
+		{
+			desc:    "typing",
+			do:      []func(){typ("hello"), typ(" "), typ("world")},
+			content: "hello world",
+			start:   11, end: 11,
+		},
+		{
+			desc:    "word left",
+			do:      []func(){press(key.LeftControl, key.Left)},
+			content: "hello world",
+			start:   6, end: 6,
+		},
+		{
+			desc:    "select to end",
+			do:      []func(){press(key.LeftShift, key.End)},
+			content: "hello world",
+			start:   6, end: 11,
+		},
+		{
+			desc:    "replace selection",
+			do:      []func(){typ("the"), typ("re")},
+			content: "hello there",
+			start:   11, end: 11,
+		},
+		{
+			desc:    "undo coalesced typing",
+			do:      []func(){press(key.LeftControl, key.Z)},
+			content: "hello world",
+			start:   6, end: 11,
+		},
+		{
+			desc:    "redo",
+			do:      []func(){press(key.RightControl, key.Y)},
+			content: "hello there",
+			start:   11, end: 11,
+		},
+		{
+			desc:    "home and shift word right",
+			do:      []func(){press(key.Home), press(key.LeftShift, key.LeftControl, key.Right)},
+			content: "hello there",
+			start:   0, end: 6,
+		},
+		{
+			desc:    "collapse",
+			do:      []func(){press(key.Right)},
+			content: "hello there",
+			start:   6, end: 6,
+		},
+		{
+			desc:    "word backspace",
+			do:      []func(){press(key.LeftControl, key.Backspace)},
+			content: "there",
+			start:   0, end: 0,
+		},
+		{
+			desc:    "select all and delete",
+			do:      []func(){press(key.LeftControl, key.A), press(key.Delete)},
+			content: "",
+			start:   0, end: 0,
+		},
+		{
+			desc:    "undo delete",
+			do:      []func(){press(key.LeftControl, key.Z)},
+			content: "there",
+			start:   0, end: 5,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			for _, do := range tc.do {
+				do()
+			}
+			if string(a.Content) != tc.content || a.Start != tc.start || a.End != tc.end {
+				t.Errorf("\n%q %d %d\n%q %d %d", string(a.Content), a.Start, a.End, tc.content, tc.start, tc.end)
+			}
+		})
+	}
+}
+
+func TestAreaValidation(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		style     string
+		validator Validator
+		typed     []string
+		content   string
+		invalid   int
+	}{
+		{
+			desc:    "max length",
+			style:   "max_length: 3;",
+			typed:   []string{"ab", "cd", "c"},
+			content: "abc",
+			invalid: 1,
+		},
+		{
+			desc:    "numeric",
+			style:   "numeric: int; min_value: -10; max_value: 50;",
+			typed:   []string{"-", "a", "1", "5", "4"},
+			content: "-1",
+			invalid: 3,
+		},
+		{
+			desc:    "numeric growing to range",
+			style:   "numeric: float; min_value: 10; max_value: 100;",
+			typed:   []string{"1", ".", "5", "e"},
+			content: "1.5",
+			invalid: 1,
+		},
+		{
+			desc:    "pattern",
+			style:   "pattern: lower;",
+			typed:   []string{"ab", "C", "d"},
+			content: "abd",
+			invalid: 1,
+		},
+		{
+			desc: "callback",
+			validator: ValidatorFunc(func(content string) error {
+				if strings.Contains(content, " ") {
+					return errors.New("no spaces")
+				}
+				return nil
+			}),
+			typed:   []string{"a", " ", "b"},
+			content: "ab",
+			invalid: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			s := NEmptyScene()
+			s.Assets.Markdowns = map[string]*txt.Markdown{"default": txt.NMarkdown()}
+			s.Assets.Patterns = map[string]string{"lower": "[a-z]*"}
+			s.Parser = NParser()
+			if err := s.Root.AddGoml([]byte(`<area name="input" style="size: 100 50; ` + tc.style + `"/>`)); err != nil {
+				t.Fatal(err)
+			}
+
+			var p Processor
+			p.SetScene(s)
+			p.SetFrame(mat.A(0, 0, 100, 100))
+			p.Resize()
+
+			e, _ := s.Root.Child("input")
+			a := e.Module.(*Area)
+			if tc.validator != nil {
+				a.Validators = append(a.Validators, tc.validator)
+			}
+			s.Focus(e)
+
+			var invalid, changed int
+			e.Listen(Invalid, func(interface{}) { invalid++ })
+			e.Listen(TextChanged, func(interface{}) { changed++ })
+
+			var f inp.Fake
+			for _, typed := range tc.typed {
+				f.Type(typed)
+				f.Update()
+				p.Update(&f, .01)
+			}
+
+			if string(a.Content) != tc.content || invalid != tc.invalid || changed != len(tc.typed)-tc.invalid {
+				t.Errorf("\n%q %d %d\n%q %d", string(a.Content), invalid, changed, tc.content, tc.invalid)
+			}
+		})
+	}
+}
+
+func TestAreaPassword(t *testing.T) {
+	s := NEmptyScene()
+	s.Assets.Markdowns = map[string]*txt.Markdown{"default": txt.NMarkdown()}
+	s.Parser = NParser()
+	if err := s.Root.AddGoml([]byte(`<area name="input" style="size: 100 50; password: true; password_char: 35;"/>`)); err != nil {
+		t.Fatal(err)
+	}
+
+	var p Processor
+	p.SetScene(s)
+	p.SetFrame(mat.A(0, 0, 100, 100))
+	p.Resize()
+
+	e, _ := s.Root.Child("input")
+	a := e.Module.(*Area)
+	s.Focus(e)
+
+	var f inp.Fake
+	f.Type("secret")
+	f.Update()
+	p.Update(&f, .01)
+	p.Resize()
+
+	if string(a.Content) != "secret" || string(a.Compiled) != "######" {
+		t.Errorf("\n%q %q\n%q %q", string(a.Content), string(a.Compiled), "secret", "######")
+	}
+}
+
+func TestMouseEvents(t *testing.T) {
+	s := NEmptyScene()
+	s.Parser = NParser()
+	err := s.Root.AddGoml([]byte(`
+<div name="under" style="size: 100 100; relative: true;">
+	<div name="button" style="size: 50 50;"/>
+</>
+<div name="popup" style="size: 30 30; relative: true;"/>
+<scroll name="scroll" style="size: 20 20; relative: true; offset: 0 70; resizing_y: ignore;">
+	<div name="inner" style="size: 20 50;"/>
+</>
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var p Processor
+	p.SetScene(s)
+	p.SetFrame(mat.A(0, 0, 100, 100))
+	p.Resize()
+
+	get := func(name string) *Element {
+		e, ok := s.Root.Child(name)
+		if !ok {
+			t.Fatal(name)
+		}
+		return e
+	}
+	under, button, popup, inner := get("under"), get("under.button"), get("popup"), get("scroll.inner")
+
+	hits := []struct {
+		desc string
+		pos  mat.Vec
+		hit  *Element
+	}{
+		{"popup over button", mat.V(10, 10), popup},
+		{"button", mat.V(40, 40), button},
+		{"parent", mat.V(80, 60), under},
+		{"inside scroll", mat.V(10, 80), inner},
+		{"clipped by scroll", mat.V(10, 95), under},
+		{"outside", mat.V(10, 110), nil},
+	}
+	for _, tc := range hits {
+		t.Run(tc.desc, func(t *testing.T) {
+			if hit := s.ElementAt(tc.pos); hit != tc.hit {
+				t.Errorf("\n%v\n%v", hit, tc.hit)
+			}
+		})
+	}
+
+	var log []string
+	listen := func(e *Element, name string, stop bool) {
+		e.Listen(name, func(i interface{}) {
+			ev := i.(*MouseEvent)
+			log = append(log, name+" "+ev.Target.Name()+" "+ev.Current.Name())
+			if stop {
+				ev.Stop()
+			}
+		})
+	}
+	for _, name := range []string{MouseDown, MouseUp, MouseClick} {
+		listen(button, name, false)
+		listen(under, name, name == MouseUp)
+		listen(&s.Root, name, false)
+	}
+	listen(popup, MouseEntered, false)
+	listen(popup, MouseExited, false)
+	listen(button, MouseEntered, false)
+	listen(under, MouseWheel, true)
+
+	var f inp.Fake
+	frame := func(do func()) {
+		do()
+		f.Update()
+		p.Update(&f, .01)
+	}
+	frame(func() { f.Move(mat.V(40, 40)) })
+	frame(func() { f.Press(key.MouseLeft) })
+	frame(func() { f.Release(key.MouseLeft) })
+	frame(func() { f.Move(mat.V(10, 10)); f.Scroll(mat.V(0, 1)) })
+	frame(func() { f.Press(key.MouseLeft) })
+	frame(func() { f.Move(mat.V(40, 40)) })
+	frame(func() { f.Release(key.MouseLeft) })
+
+	expected := []string{
+		"mouse_entered button button",
+		"mouse_down button button",
+		"mouse_down button under",
+		"mouse_down button ",
+		"mouse_up button button",
+		"mouse_up button under",
+		"mouse_click button button",
+		"mouse_click button under",
+		"mouse_click button ",
+		"mouse_entered popup popup",
+		"mouse_down popup ",
+		"mouse_exited popup popup",
+		"mouse_entered button button",
+		"mouse_up button button",
+		"mouse_up button under",
+		"mouse_click  ", // root is common parent of popup and button
+	}
+	if !reflect.DeepEqual(log, expected) {
+		t.Errorf("\n%q\n%q", log, expected)
+	}
+
+	if !button.Hovering || !under.Hovering || popup.Hovering {
+		t.Error(button.Hovering, under.Hovering, popup.Hovering)
+	}
+}
+
+func TestLayers(t *testing.T) {
+	s := NEmptyScene()
+	s.Assets.Markdowns = map[string]*txt.Markdown{"default": txt.NMarkdown()}
+	s.Parser = NParser()
+	err := s.Root.AddGoml([]byte(`<div name="base" tooltip="hello" tab_index="0" style="size: 100 100;"/>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.Layer(PopupLayer).AddGoml([]byte(`
+<div name="dialog" style="size: 40 40; relative: true; modal: true; dismissible: true;">
+	<div name="ok" tab_index="0" style="size: 10 10;"/>
+</>
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var p Processor
+	p.SetScene(s)
+	p.SetFrame(mat.A(0, 0, 100, 100))
+	p.Resize()
+
+	base, _ := s.Root.Child("base")
+	dialog, _ := s.Layer(PopupLayer).Child("dialog")
+	ok, _ := dialog.Child("ok")
+
+	if s.ModalLayer() != PopupLayer {
+		t.Error(s.ModalLayer())
+	}
+	if hit := s.ElementAt(mat.V(80, 80)); hit != s.Layer(PopupLayer) {
+		t.Error("modal does not block base layer:", hit)
+	}
+	if hit := s.ElementAt(mat.V(5, 5)); hit != ok {
+		t.Error(hit)
+	}
+	if order := s.TabOrder(); len(order) != 1 || order[0] != ok {
+		t.Error(order)
+	}
+
+	dismissed := 0
+	dialog.Listen(Dismissed, func(interface{}) { dismissed++ })
+
+	var f inp.Fake
+	frame := func(do func()) {
+		do()
+		f.Update()
+		p.Update(&f, .2)
+	}
+	frame(func() { f.Move(mat.V(5, 5)); f.Press(key.MouseLeft) })
+	frame(func() { f.Release(key.MouseLeft) })
+	if dialog.Hidden() || dismissed != 0 {
+		t.Error("click inside dismissed the dialog")
+	}
+
+	frame(func() { f.Move(mat.V(80, 80)); f.Press(key.MouseLeft) })
+	frame(func() { f.Release(key.MouseLeft) })
+	if !dialog.Hidden() || dismissed != 1 || s.ModalLayer() != BaseLayer {
+		t.Error("click outside did not dismiss the dialog")
+	}
+	if !base.Hovering {
+		t.Error("base layer still blocked")
+	}
+
+	for i := 0; i < 3; i++ {
+		frame(func() {})
+	}
+	tip := s.Tooltip()
+	if tip.Hidden() || string(tip.Module.(*Text).Content) != "hello" {
+		t.Error("tooltip not shown")
+	}
+	if tip.Frame.Min != mat.V(80, 80).Add(TooltipOffset).Add(tip.Margin.Min) {
+		t.Error(tip.Frame)
+	}
+
+	frame(func() { f.Move(mat.V(200, 200)) })
+	if !tip.Hidden() {
+		t.Error("tooltip not hidden")
+	}
+}
+
+func TestDragAndDrop(t *testing.T) {
+	s := NEmptyScene()
+	s.Parser = NParser()
+	err := s.Root.AddGoml([]byte(`
+<div name="list">
+	<div name="a" style="size: 100 10; draggable: true; drop_target: true; background: white;"/>
+	<div name="b" style="size: 100 10; draggable: true; drop_target: true; background: white;"/>
+	<div name="c" style="size: 100 10; draggable: true; drop_target: true; background: white;"/>
+</>
+<div name="locked" style="size: 100 10; drop_target: true;"/>
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var p Processor
+	p.SetScene(s)
+	p.SetFrame(mat.A(0, 0, 100, 100))
+	p.Resize()
+
+	list, _ := s.Root.Child("list")
+	locked, _ := s.Root.Child("locked")
+	a, _ := list.Child("a")
+	c, _ := list.Child("c")
+
+	var log []string
+	for _, e := range []*Element{a, c, locked} {
+		e := e
+		for _, name := range []string{DragStart, DragEnter, DragLeave, Drop, DragEnd} {
+			name := name
+			e.Listen(name, func(i interface{}) {
+				ev := i.(*DragEvent)
+				target := ""
+				if ev.Target != nil {
+					target = ev.Target.Name()
+				}
+				log = append(log, fmt.Sprint(name, " ", e.Name(), " ", target, " ", ev.Rejected()))
+				switch {
+				case name == DragEnter && e == locked:
+					ev.Reject()
+				case name == Drop:
+					ev.Source.SetIndex(ev.Target.Index())
+				}
+			})
+		}
+	}
+
+	center := func(e *Element) mat.Vec {
+		return e.Frame.Center()
+	}
+
+	var f inp.Fake
+	frame := func(do func()) {
+		do()
+		f.Update()
+		p.Update(&f, .01)
+	}
+
+	frame(func() { f.Move(center(a)); f.Press(key.MouseLeft) })
+	frame(func() { f.Move(center(a).Add(mat.V(0, -2))) })
+	if s.Dragged() != nil {
+		t.Error("drag started before threshold")
+	}
+	plain := len(s.Batch.Vertexes)
+	frame(func() { f.Move(center(locked)) })
+	if s.Dragged() != a || s.DragTarget() != locked {
+		t.Error(s.Dragged(), s.DragTarget())
+	}
+	if len(s.Batch.Vertexes) <= plain {
+		t.Error("ghost is not drawn")
+	}
+	frame(func() { f.Move(center(locked)); f.Release(key.MouseLeft) })
+
+	frame(func() { f.Move(center(a)); f.Press(key.MouseLeft) })
+	frame(func() { f.Move(center(c)) })
+	frame(func() { f.Release(key.MouseLeft) })
+
+	expected := []string{
+		"drag_start a  false",
+		"drag_enter locked locked false",
+		"drag_leave locked locked false",
+		"drag_end a locked true",
+		"drag_start a  false",
+		"drag_enter c c false",
+		"drop c c false",
+		"drag_end a c false",
+	}
+	if !reflect.DeepEqual(log, expected) {
+		t.Errorf("\n%q\n%q", log, expected)
+	}
+
+	if a.Index() != 2 || s.Dragged() != nil {
+		t.Error(a.Index(), s.Dragged())
+	}
+}
+
+func TestLayout(t *testing.T) {
+	testCases := []struct {
+		desc, source, dump string
+	}{
+		{
+			desc: "vertical",
+			source: `
+<div name="a" style="size: 50 20;"/>
+<div name="b" style="size: fill 30; margin: 5;"/>
+`,
+			dump: `root *ui.ModuleBase A(0 0 100 60)
+	a div A(0 40 50 60)
+	b div A(5 5 95 35) margin A(5 5 5 5)
+`,
+		},
+		{
+			desc: "horizontal with padding",
+			source: `
+<div name="row" style="composition: horizontal; padding: 10; size: fill 0;">
+	<div name="a" style="size: 20 20;"/>
+	<div name="b" style="size: fill 20;"/>
+</>
+`,
+			dump: `root *ui.ModuleBase A(0 0 100 40)
+	row div A(0 0 100 40) padding A(10 10 10 10)
+		a div A(10 10 30 30)
+		b div A(30 10 90 30)
+`,
+		},
+		{
+			desc:   "centered",
+			source: `<div name="a" style="size: 20 20; margin: fill;"/>`,
+			dump: `root *ui.ModuleBase A(0 0 20 20)
+	a div A(40 40 60 60) margin A(40 40 40 40)
+`,
+		},
+		{
+			desc: "percent",
+			source: `
+<div name="a" style="size: 50 percent 10;"/>
+<div name="b" style="size: 25 percent 20 percent; padding: 5;"/>
+`,
+			dump: `root *ui.ModuleBase A(0 0 50 40)
+	a div A(0 30 50 40)
+	b div A(0 0 35 30) padding A(5 5 5 5)
+`,
+		},
+		{
+			desc: "min and max",
+			source: `
+<div name="a" style="size: fill 10; max_size: 60 100;"/>
+<div name="b" style="size: 10 10; min_size: 30 0;"/>
+<div name="c" style="size: 50 percent 10; resizing: exact; max_size: 20;">
+	<div name="d" style="size: 40 40;"/>
+</>
+`,
+			dump: `root *ui.ModuleBase A(0 0 60 40)
+	a div A(0 30 60 40)
+	b div A(0 20 30 30)
+	c div A(0 0 20 20)
+		d div A(0 0 40 40)
+`,
+		},
+		{
+			desc:   "aspect ratio",
+			source: `<div name="a" style="size: 40 percent 0; aspect_ratio: 2; padding: 2;"/>`,
+			dump: `root *ui.ModuleBase A(0 0 44 22)
+	a div A(0 0 44 22) padding A(2 2 2 2)
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			s := NEmptyScene()
+			s.Parser = NParser()
+			if err := s.Root.AddGoml([]byte(tc.source)); err != nil {
+				t.Fatal(err)
+			}
+			s.Layout(mat.A(0, 0, 100, 100))
+			if dump := s.Root.Dump().String(); dump != tc.dump {
+				t.Errorf("\n%s\n%s", dump, tc.dump)
+			}
+		})
+	}
+}
+
+func TestLayoutDumpJson(t *testing.T) {
+	s := NEmptyScene()
+	s.Parser = NParser()
+	err := s.Root.AddGoml([]byte(`<div name="a" style="size: 100 20;"><div name="b" style="size: 10 10;"/></>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Layout(mat.A(0, 0, 100, 100))
+
+	bts, err := json.Marshal(s.Root.Dump())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dump LayoutDump
+	if err := json.Unmarshal(bts, &dump); err != nil {
+		t.Fatal(err)
+	}
+
+	b, ok := dump.Find("a.b")
+	if !ok || b.Path != "root.a.b" || b.Frame != mat.A(0, 0, 10, 10) {
+		t.Error(ok, b)
+	}
+	if _, ok := dump.Find("a.c"); ok {
+		t.Error("found missing element")
+	}
+}
+
+// relayoutScene builds scene of rows, each row is layout boundary with text counter
+func relayoutScene(rows, cells int) (*Scene, *Processor) {
+	var sb strings.Builder
+	for i := 0; i < rows; i++ {
+		fmt.Fprintf(&sb, `<div name="row%d" style="size: 400 20; resizing: ignore; composition: horizontal;">`, i)
+		sb.WriteString(`<text name="counter" style="size: 40 fill;"/>`)
+		for j := 0; j < cells; j++ {
+			sb.WriteString(`<div style="size: fill 10; margin: 1;"/>`)
+		}
+		sb.WriteString(`</>`)
+	}
+
+	s := NEmptyScene()
+	s.Assets.Markdowns = map[string]*txt.Markdown{"default": txt.NMarkdown()}
+	s.Parser = NParser()
+	if err := s.Root.AddGoml([]byte(sb.String())); err != nil {
+		panic(err)
+	}
+
+	p := &Processor{}
+	p.SetScene(s)
+	p.SetFrame(mat.A(0, 0, 400, 2000))
+	p.Resize()
+
+	return s, p
+}
+
+func TestRelayout(t *testing.T) {
+	s, p := relayoutScene(3, 2)
+	row, _ := s.Root.Child("row1")
+	other, _ := s.Root.Child("row0")
+	counter, _ := row.Child("counter")
+
+	if !row.IsBoundary() || s.Root.IsBoundary() {
+		t.Error("wrong boundaries")
+	}
+
+	// other row must not be touched
+	sentinel := mat.A(-1, -1, -1, -1)
+	other.Frame = sentinel
+
+	counter.Module.(*Text).SetText("42")
+	var f inp.Fake
+	f.Update()
+	p.Update(&f, .01)
+
+	if other.Frame != sentinel {
+		t.Error("element outside the boundary was resized")
+	}
+	if len(s.relayout) != 0 {
+		t.Error("relayout queue was not cleared")
+	}
+
+	other.Frame = mat.ZA
+	incremental := s.Root.Dump().String()
+	p.Resize()
+	if full := s.Root.Dump().String(); incremental[strings.Index(incremental, "\trow1"):] != full[strings.Index(full, "\trow1"):] {
+		t.Errorf("\n%s\n%s", incremental, full)
+	}
+}
+
+func TestRelayoutBounds(t *testing.T) {
+	s, p := relayoutScene(3, 2)
+	for _, name := range []string{"row0.counter", "row2.counter", "row1", "row2"} {
+		e, _ := s.Root.Child(name)
+		e.Relayout()
+	}
+	s.Root.Relayout()
+	p.relayout()
+
+	if len(p.bounds) != 1 || p.bounds[0] != &s.Root {
+		t.Error(p.bounds)
+	}
+}
+
+func TestRedrawPart(t *testing.T) {
+	s, p := relayoutScene(3, 2)
+	e, _ := s.Root.Child("row1.counter")
+	text := e.Module.(*Text)
+
+	var f inp.Fake
+	f.Update()
+	text.SetText("10")
+	p.Update(&f, .01)
+
+	gen := s.drawGen
+	text.SetText("42")
+	p.Update(&f, .01)
+	if s.drawGen != gen {
+		t.Fatal("scene was redrawn fully")
+	}
+
+	vertexes := append(s.Batch.Vertexes[:0:0], s.Batch.Vertexes...)
+	indices := append(s.Batch.Indices[:0:0], s.Batch.Indices...)
+	p.Redraw()
+	if !reflect.DeepEqual(vertexes, s.Batch.Vertexes) || !reflect.DeepEqual(indices, s.Batch.Indices) {
+		t.Errorf("\n%v\n%v\n%v\n%v", vertexes, s.Batch.Vertexes, indices, s.Batch.Indices)
+	}
+}
+
+func BenchmarkRelayout(b *testing.B) {
+	var f inp.Fake
+	f.Update()
+
+	b.Run("full", func(b *testing.B) {
+		s, p := relayoutScene(100, 8)
+		row, _ := s.Root.Child("row50.counter")
+		text := row.Module.(*Text)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			text.SetText(strconv.Itoa(i))
+			s.Resize.Notify()
+			p.Update(&f, .01)
+		}
+	})
+
+	b.Run("incremental", func(b *testing.B) {
+		s, p := relayoutScene(100, 8)
+		row, _ := s.Root.Child("row50.counter")
+		text := row.Module.(*Text)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			text.SetText(strconv.Itoa(i))
+			p.Update(&f, .01)
+		}
+	})
+}
+
+func TestMedia(t *testing.T) {
+	s := NEmptyScene()
+	s.Assets.Markdowns = map[string]*txt.Markdown{"default": txt.NMarkdown(), "default@2": txt.NMarkdown()}
+	s.Assets.Styles = goss.Styles{}
+	s.Parser = NParser()
+	err := s.AddGoss([]byte(`
+box{
+	size: 10 10;
+}
+box_compact{
+	media: max_width 50;
+	target: box;
+	size: 5 5;
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Root.AddGoml([]byte(`<div name="box" styles="box"/><text name="text" style="text_margin: 0;"/><div name="grid" style="columns: 10 fill; rows: 5;"/>`)); err != nil {
+		t.Fatal(err)
+	}
+
+	var p Processor
+	p.SetScene(s)
+	box, _ := s.Root.Child("box")
+	text, _ := s.Root.Child("text")
+	grid, _ := s.Root.Child("grid")
+
+	testCases := []struct {
+		desc  string
+		frame mat.AABB
+		scale float64
+		size  mat.Vec
+		mkd   string
+	}{
+		{"wide", mat.A(0, 0, 100, 100), 1, mat.V(10, 10), "default"},
+		{"compact", mat.A(0, 0, 40, 100), 1, mat.V(5, 5), "default"},
+		{"scaled wide", mat.A(0, 0, 200, 100), 2, mat.V(20, 20), "default@2"},
+		{"scaled compact", mat.A(0, 0, 100, 100), 2, mat.V(10, 10), "default@2"},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			p.SetFrame(tC.frame)
+			p.SetScale(tC.scale)
+			p.Resize()
+
+			if box.Frame.Size() != tC.size {
+				t.Errorf("\n%v\n%v", box.Frame.Size(), tC.size)
+			}
+			tx := text.Module.(*Text)
+			if tx.Markdown != s.Assets.Markdowns[tC.mkd] || tx.Scl != mat.V(1, 1) {
+				t.Errorf("\n%v\n%v", tx.Scl, tC.mkd)
+			}
+			if grid.Columns[0].Value != 10*tC.scale || grid.Columns[1].Value != 1 || grid.Rows[0].Value != 5*tC.scale {
+				t.Errorf("\n%v %v\n%v", grid.Columns, grid.Rows, tC.scale)
+			}
+		})
+	}
+
+	p.SetScaleBase(mat.V(50, 50))
+	if p.Scale() != 2 || s.Scale() != 2 {
+		t.Error(p.Scale(), s.Scale())
+	}
+}
+
+func TestTheme(t *testing.T) {
+	s := NEmptyScene()
+	s.Assets.Styles = goss.Styles{}
+	s.Parser = NParser()
+	err := s.AddGoss([]byte(`
+variables{
+	pad: 2 4;
+	size: 10;
+}
+big{
+	size: 20;
+}
+box{
+	padding: var_pad;
+	size: var_size;
+}
+broken{
+	size: var_missing;
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var logged []error
+	s.Root.Listen(Error, func(i interface{}) {
+		logged = append(logged, i.(ErrorEventData).Err)
+	})
+
+	if err := s.Root.AddGoml([]byte(`<div name="box" styles="box"/><div name="broken" styles="broken"/>`)); err != nil {
+		t.Fatal(err)
+	}
+	box, _ := s.Root.Child("box")
+
+	if box.Padding != mat.A(2, 4, 2, 4) || box.Size != mat.V(10, 10) {
+		t.Error(box.Padding, box.Size)
+	}
+	if len(logged) != 1 || !ErrMissingVariable.SameSurface(logged[0]) {
+		t.Error(logged)
+	}
+
+	if err := s.SetTheme("big"); err != nil {
+		t.Fatal(err)
+	}
+	if box.Size != mat.V(20, 20) || box.Padding != mat.A(2, 4, 2, 4) {
+		t.Error(box.Padding, box.Size)
+	}
+	if v := s.Assets.Styles["box"]["size"]; len(v) != 1 || v[0] != "var_size" {
+		t.Error("style in assets was modified", v)
+	}
+
+	if err := s.SetTheme("dark"); !ErrMissingTheme.SameSurface(err) {
+		t.Error(err)
+	}
+	if err := s.SetTheme(); err != nil || box.Size != mat.V(10, 10) {
+		t.Error(err, box.Size)
+	}
+}
+
+func TestComponent(t *testing.T) {
+	s := NEmptyScene()
+	s.Assets.Markdowns = map[string]*txt.Markdown{"default": txt.NMarkdown()}
+	s.Parser = NParser()
+	_, err := s.Parse([]byte(`
+<component name="dialog" title="Dialog" look="dialog">
+	<div name="frame" styles="$look" style="size: 100 50;">
+		<text name="title" text="$title - $titles"/>
+		<div name="content"><slot/></>
+		<button name="ok"/>
+	</>
+</>
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = s.Parse([]byte(`
+<component name="confirm" question="?">
+	<dialog title="Confirm">
+		<text name="question" text="$question"/>
+		<slot/>
+	</>
+</>
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.AddComponent(Component{Name: "loop", Template: goml.Element{Name: "div", Children: []goml.Element{{Name: "loop"}}}})
+
+	err = s.Root.AddGoml([]byte(`
+<dialog name="a" title="Hello" look="dialog big" styles="small wide" style="margin: 5;">
+	<text name="body" text="world"/>
+	<div/>
+</>
+<dialog/>
+<confirm name="c" question="Sure?" tooltip="tip"><div name="extra"/></>
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		path, text string
+	}{
+		{"a.title", "Hello - $titles"},
+		{"a.content.body", "world"},
+		{"1.title", "Dialog - $titles"},
+		{"c.content.question", "Sure?"},
+		{"c.title", "Confirm - $titles"},
+	}
+
+	for _, tC := range testCases {
+		e, ok := s.Root.Child(tC.path)
+		if !ok {
+			t.Error(tC.path)
+			continue
+		}
+		if text := string(e.Module.(*Text).Content); text != tC.text {
+			t.Errorf("\n%v\n%v", text, tC.text)
+		}
+	}
+
+	a, _ := s.Root.Child("a")
+	if !reflect.DeepEqual(a.Styles, []string{"dialog", "big", "small", "wide"}) || a.Size != mat.V(100, 50) || a.Margin != mat.A(5, 5, 5, 5) {
+		t.Error(a.Styles, a.Size, a.Margin)
+	}
+	if b, _ := s.Root.Child("1"); !reflect.DeepEqual(b.Styles, []string{"dialog"}) {
+		t.Error(b.Styles)
+	}
+	if _, ok := s.Root.Child("a.content.1"); !ok {
+		t.Error("unnamed slot child is missing")
+	}
+	if _, ok := s.Root.Child("c.content.extra"); !ok {
+		t.Error("nested slot child is missing")
+	}
+	if c, _ := s.Root.Child("c"); c.Raw.Attributes.Ident("tooltip", "") != "tip" {
+		t.Error(c.Raw.Attributes)
+	}
+
+	if err := s.Root.AddGoml([]byte(`<loop/>`)); err == nil || !strings.Contains(err.Error(), "contains itself") {
+		t.Error(err)
+	}
+	if _, err := s.Parse([]byte(`<component name="empty"/>`)); !ErrComponent.SameSurface(err) {
+		t.Error(err)
+	}
+}
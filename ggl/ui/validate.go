@@ -0,0 +1,183 @@
+package ui
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jakubDoka/gogen/str"
+	"github.com/jakubDoka/sterr"
+)
+
+// validation errors, they are passed to Invalid event
+var (
+	ErrTooLong    = sterr.New("content is longer then %d characters")
+	ErrNotNumber  = sterr.New("'%s' is not a number")
+	ErrOutOfRange = sterr.New("%v is not in range %v..%v")
+	ErrNoMatch    = sterr.New("'%s' does not match '%s'")
+	ErrPattern    = sterr.New("invalid pattern '%s'")
+
+	ErrMissingValidator = sterr.New("validator with name '%s' is not present in assets")
+	ErrMissingPattern   = sterr.New("pattern with name '%s' is not present in assets")
+)
+
+// Validator checks content of Area before change is applied, returned error rejects the
+// change
+type Validator interface {
+	Validate(content string) error
+}
+
+// ValidatorFunc is Validator from plain function
+type ValidatorFunc func(content string) error
+
+// Validate implements Validator interface
+func (v ValidatorFunc) Validate(content string) error {
+	return v(content)
+}
+
+// MaxLength rejects content longer then given amount of characters, zero or less means
+// no limit
+type MaxLength int
+
+// Validate implements Validator interface
+func (m MaxLength) Validate(content string) error {
+	if m > 0 && utf8.RuneCountInString(content) > int(m) {
+		return ErrTooLong.Args(int(m))
+	}
+	return nil
+}
+
+// Numeric accepts only numbers in Min..Max range, content that is not finished yet such as
+// empty string or lone minus is accepted, so is the number out of range if typing more
+// digits can get it back to range
+type Numeric struct {
+	Integer  bool
+	Min, Max float64
+}
+
+// Validate implements Validator interface
+func (n Numeric) Validate(content string) error {
+	switch content {
+	case "", "-", "+":
+		return nil
+	case ".", "-.", "+.":
+		if !n.Integer {
+			return nil
+		}
+	}
+
+	var (
+		v   float64
+		err error
+	)
+	if n.Integer {
+		var i int64
+		i, err = strconv.ParseInt(content, 10, 64)
+		v = float64(i)
+	} else if !strings.ContainsAny(content, "eEinfINFxXpP_") { // only plain decimals
+		v, err = strconv.ParseFloat(content, 64)
+	} else {
+		err = strconv.ErrSyntax
+	}
+	if err != nil {
+		return ErrNotNumber.Args(content)
+	}
+
+	// adding digits only grows the magnitude so only one side of range can be fixed
+	if v > n.Max && v >= 0 || v < n.Min && v <= 0 {
+		return ErrOutOfRange.Args(v, n.Min, n.Max)
+	}
+
+	return nil
+}
+
+// Pattern accepts only content fully matching the regex, remember that user types content
+// character by character so pattern has to accept unfinished input
+type Pattern struct {
+	*regexp.Regexp
+}
+
+// NPattern compiles pattern, whole content has to match so pattern is wrapped into ^(?:...)$
+func NPattern(pattern string) (Pattern, error) {
+	r, err := regexp.Compile("^(?:" + pattern + ")$")
+	return Pattern{r}, err
+}
+
+// Validate implements Validator interface
+func (p Pattern) Validate(content string) error {
+	if !p.MatchString(content) {
+		return ErrNoMatch.Args(content, p.String())
+	}
+	return nil
+}
+
+// Validate runs all validators of area on content, validators from style go first
+func (a *Area) Validate(content string) error {
+	for _, vs := range [...][]Validator{a.styled, a.Validators} {
+		for _, v := range vs {
+			if err := v.Validate(content); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validate checks content that would be result of replacing the range with text
+func (a *Area) validate(start, end int, text str.String) error {
+	if len(a.styled) == 0 && len(a.Validators) == 0 {
+		return nil
+	}
+	return a.Validate(string(a.Content[:start]) + string(text) + string(a.Content[end:]))
+}
+
+// initValidators builds validators from style
+func (a *Area) initValidators(e *Element) {
+	a.styled = a.styled[:0]
+
+	if l := e.Int("max_length", 0); l > 0 {
+		a.styled = append(a.styled, MaxLength(l))
+	}
+
+	switch e.Ident("numeric", "none") {
+	case "int":
+		a.styled = append(a.styled, Numeric{true, e.Float("min_value", math.Inf(-1)), e.Float("max_value", math.Inf(1))})
+	case "float":
+		a.styled = append(a.styled, Numeric{false, e.Float("min_value", math.Inf(-1)), e.Float("max_value", math.Inf(1))})
+	}
+
+	if name := e.Ident("pattern", ""); name != "" {
+		pattern, ok := a.Scene.Assets.Patterns[name]
+		if !ok {
+			a.Scene.Log(e, ErrMissingPattern.Args(name))
+		} else if p, err := NPattern(pattern); err != nil {
+			a.Scene.Log(e, ErrPattern.Args(pattern).Wrap(err))
+		} else {
+			a.styled = append(a.styled, p)
+		}
+	}
+
+	if name := e.Ident("validator", ""); name != "" {
+		v, ok := a.Scene.Assets.Validators[name]
+		if ok {
+			a.styled = append(a.styled, v)
+		} else {
+			a.Scene.Log(e, ErrMissingValidator.Args(name))
+		}
+	}
+}
+
+// mask returns string of same length as s made of r
+func mask(s str.String, r rune) str.String {
+	m := make(str.String, len(s))
+	for i, c := range s {
+		if c == '\n' {
+			m[i] = c // keep the lines
+		} else {
+			m[i] = r
+		}
+	}
+	return m
+}
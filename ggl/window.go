@@ -4,6 +4,7 @@ import (
 	"log"
 	"runtime"
 
+	"github.com/jakubDoka/mlok/ggl/input"
 	"github.com/jakubDoka/mlok/ggl/key"
 	"github.com/jakubDoka/mlok/mat"
 
@@ -25,21 +26,21 @@ type Setup interface {
 }
 
 // Window enables use of almost all other structs avaliable in this package, Window initializes opengl context.
+// Window also implements input.Input.
 //
 type Window struct {
 	*glfw.Window
 	Mask mat.RGBA
 	Canvas
 
+	// Gamepads is polled each update, by default it reads gamepads from glfw, set it to
+	// nil if you do not need gamepads
+	Gamepads input.GamepadSource
+
 	cursorInsideWindow bool
 
-	prevInp, currInp, tempInp struct {
-		mouse   mat.Vec
-		buttons [key.Last + 1]bool
-		repeat  [key.Last + 1]bool
-		scroll  mat.Vec
-		typed   string
-	}
+	inp     input.Frames
+	tempInp input.State
 }
 
 // NWindow creates new window from WindowConfig, if config is nil, default one will  be used
@@ -100,8 +101,9 @@ func NWindow(config *WindowConfig) (*Window, error) {
 	}
 
 	win := Window{
-		Window: window,
-		Mask:   mat.Alpha(1),
+		Window:   window,
+		Mask:     mat.Alpha(1),
+		Gamepads: &GlfwGamepads{Mapping: input.StandardMapping},
 	}
 
 	win.initInput()
@@ -157,34 +159,34 @@ type WindowConfig struct {
 
 // Pressed returns whether the Button is currently pressed down.
 func (w *Window) Pressed(button key.Key) bool {
-	return w.currInp.buttons[button]
+	return w.inp.Pressed(button)
 }
 
 // JustPressed returns whether the Button has just been pressed down.
 func (w *Window) JustPressed(button key.Key) bool {
-	return w.currInp.buttons[button] && !w.prevInp.buttons[button]
+	return w.inp.JustPressed(button)
 }
 
 // JustReleased returns whether the Button has just been released up.
 func (w *Window) JustReleased(button key.Key) bool {
-	return !w.currInp.buttons[button] && w.prevInp.buttons[button]
+	return w.inp.JustReleased(button)
 }
 
 // Repeated returns whether a repeat event has been triggered on button.
 //
 // Repeat event occurs repeatedly when a button is held down for some time.
 func (w *Window) Repeated(button key.Key) bool {
-	return w.currInp.repeat[button]
+	return w.inp.Repeated(button)
 }
 
 // MousePos returns the current mouse position in the Window's Bounds.
 func (w *Window) MousePos() mat.Vec {
-	return w.currInp.mouse
+	return w.inp.MousePos()
 }
 
 // MousePrevPos returns the previous mouse position in the Window's Bounds.
 func (w *Window) MousePrevPos() mat.Vec {
-	return w.prevInp.mouse
+	return w.inp.MousePrevPos()
 }
 
 // MouseIsInside returns true if the mouse position is within the Window's Bounds.
@@ -194,21 +196,46 @@ func (w *Window) MouseIsInside() bool {
 
 // MouseScroll returns the mouse scroll amount (in both axes) since the last call to Window.Update.
 func (w *Window) MouseScroll() mat.Vec {
-	return w.currInp.scroll
+	return w.inp.MouseScroll()
 }
 
 // Typed returns the text typed on the keyboard since the last call to Window.Update.
 func (w *Window) Typed() string {
-	return w.currInp.typed
+	return w.inp.Typed()
+}
+
+// GamepadConnected returns whether gamepad is connected.
+func (w *Window) GamepadConnected(j key.Joystick) bool {
+	return w.inp.GamepadConnected(j)
+}
+
+// GamepadPressed returns whether gamepad button is currently pressed down.
+func (w *Window) GamepadPressed(j key.Joystick, button key.GamepadButton) bool {
+	return w.inp.GamepadPressed(j, button)
+}
+
+// GamepadJustPressed returns whether gamepad button has just been pressed down.
+func (w *Window) GamepadJustPressed(j key.Joystick, button key.GamepadButton) bool {
+	return w.inp.GamepadJustPressed(j, button)
+}
+
+// GamepadJustReleased returns whether gamepad button has just been released up.
+func (w *Window) GamepadJustReleased(j key.Joystick, button key.GamepadButton) bool {
+	return w.inp.GamepadJustReleased(j, button)
+}
+
+// GamepadAxis returns value of gamepad axis, no deadzone is applied.
+func (w *Window) GamepadAxis(j key.Joystick, axis key.Axis) float64 {
+	return w.inp.GamepadAxis(j, axis)
 }
 
 func (w *Window) initInput() {
 	w.Window.SetMouseButtonCallback(func(_ *glfw.Window, button glfw.MouseButton, action glfw.Action, mod glfw.ModifierKey) {
 		switch action {
 		case glfw.Press:
-			w.tempInp.buttons[key.Key(button)] = true
+			w.tempInp.Buttons[key.Key(button)] = true
 		case glfw.Release:
-			w.tempInp.buttons[key.Key(button)] = false
+			w.tempInp.Buttons[key.Key(button)] = false
 		}
 	})
 
@@ -218,40 +245,80 @@ func (w *Window) initInput() {
 		}
 		switch action {
 		case glfw.Press:
-			w.tempInp.buttons[key.Key(k)] = true
+			w.tempInp.Buttons[key.Key(k)] = true
 		case glfw.Release:
-			w.tempInp.buttons[key.Key(k)] = false
+			w.tempInp.Buttons[key.Key(k)] = false
 		case glfw.Repeat:
-			w.tempInp.repeat[key.Key(k)] = true
+			w.tempInp.Repeat[key.Key(k)] = true
 		}
 	})
 
 	w.Window.SetCursorEnterCallback(func(_ *glfw.Window, entered bool) {
 		w.cursorInsideWindow = entered
+		w.tempInp.Inside = entered
 	})
 
 	w.Window.SetCursorPosCallback(func(_ *glfw.Window, x, y float64) {
-		w.tempInp.mouse = mat.V(x, y).Sub(w.viewpot.Scaled(.5)).Mul(mat.V(1, -1))
+		w.tempInp.Mouse = mat.V(x, y).Sub(w.viewpot.Scaled(.5)).Mul(mat.V(1, -1))
 	})
 
 	w.Window.SetScrollCallback(func(_ *glfw.Window, xoff, yoff float64) {
-		w.tempInp.scroll.X += xoff
-		w.tempInp.scroll.Y += yoff
+		w.tempInp.Scroll.X += xoff
+		w.tempInp.Scroll.Y += yoff
 	})
 
 	w.Window.SetCharCallback(func(_ *glfw.Window, r rune) {
-		w.tempInp.typed += string(r)
+		w.tempInp.Typed += string(r)
 	})
 }
 
 // internal input bookkeeping
 func (w *Window) doUpdateInput() {
-	w.prevInp = w.currInp
-	w.currInp = w.tempInp
+	if w.Gamepads != nil {
+		input.Poll(w.Gamepads, &w.tempInp)
+	}
+	w.inp.Push(w.tempInp)
+	w.tempInp = w.tempInp.Next()
+}
+
+// GlfwGamepads is default gamepad source of Window, joysticks glfw knows standard mapping
+// for are read as gamepads, others are mapped with Mapping
+type GlfwGamepads struct {
+	Mapping input.Mapping
+
+	buttons []bool
+	axes    []float64
+}
+
+// Gamepad implements input.GamepadSource interface
+func (g *GlfwGamepads) Gamepad(j key.Joystick) (p input.Gamepad) {
+	js := glfw.Joystick(j)
+	if !js.Present() {
+		return
+	}
+
+	if js.IsGamepad() {
+		if st := js.GetGamepadState(); st != nil {
+			p.Connected = true
+			for i, b := range st.Buttons {
+				p.Buttons[i] = b == glfw.Press
+			}
+			for i, a := range st.Axes {
+				p.Axes[i] = float64(a)
+			}
+			return
+		}
+	}
+
+	g.buttons, g.axes = g.buttons[:0], g.axes[:0]
+	for _, b := range js.GetButtons() {
+		g.buttons = append(g.buttons, b == glfw.Press)
+	}
+	for _, a := range js.GetAxes() {
+		g.axes = append(g.axes, float64(a))
+	}
 
-	w.tempInp.repeat = [key.Last + 1]bool{}
-	w.tempInp.scroll = mat.Vec{}
-	w.tempInp.typed = ""
+	return g.Mapping.Map(g.buttons, g.axes)
 }
 
 // WindowHint allows to specify vindow hints (revolution right here)
@@ -13,6 +13,7 @@ import (
 	"strings"
 
 	"github.com/golang/freetype/truetype"
+	"github.com/jakubDoka/goml/goss"
 	"github.com/jakubDoka/sterr"
 	"golang.org/x/image/font"
 )
@@ -85,6 +86,22 @@ func (l Util) SaveJson(p string, value interface{}) error {
 	return ioutil.WriteFile(l.path(p), bts, os.ModePerm)
 }
 
+// Goss parses goss file from given path
+func (l Util) Goss(p string) (goss.Styles, error) {
+	bts, err := l.ReadFile(l.path(p))
+	if err != nil {
+		return nil, err
+	}
+
+	var parser goss.Parser
+	return parser.Parse(bts)
+}
+
+// Save writes data to file on given path
+func (l Util) Save(p string, data []byte) error {
+	return ioutil.WriteFile(l.path(p), data, os.ModePerm)
+}
+
 // LoadTTF loads TTF file into font.Face
 func (l Util) LoadTTF(p string, size float64) (font.Face, error) {
 	bytes, err := l.ReadFile(l.path(p))
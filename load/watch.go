@@ -0,0 +1,107 @@
+package load
+
+import (
+	"os"
+	"time"
+
+	"github.com/jakubDoka/sterr"
+)
+
+// Watcher related errors
+var (
+	ErrReload = sterr.New("failed to reload %s")
+)
+
+// Watcher polls files for changes, it uses only os.Stat so it works on every platform
+// but it is meant only for development, for example to reload ui while game is running
+//
+//	w := load.NWatcher(time.Second / 2)
+//	w.Watch("ui.goss", func(path string) error {
+//		bts, err := os.ReadFile(path)
+//		...
+//	})
+//	for ... {
+//		err := w.Poll()
+//	}
+type Watcher struct {
+	Period time.Duration
+
+	last  time.Time
+	files map[string]*watched
+}
+
+type watched struct {
+	mod      time.Time
+	size     int64
+	handlers []func(path string) error
+}
+
+// NWatcher creates watcher that checks files at most once per period
+func NWatcher(period time.Duration) *Watcher {
+	return &Watcher{
+		Period: period,
+		files:  map[string]*watched{},
+	}
+}
+
+// Watch registers handler that is called when file on given path changes, multiple
+// handlers can watch the same file
+func (w *Watcher) Watch(path string, handler func(path string) error) {
+	f, ok := w.files[path]
+	if !ok {
+		f = &watched{}
+		f.mod, f.size = stat(path)
+		w.files[path] = f
+	}
+	f.handlers = append(f.handlers, handler)
+}
+
+// Unwatch removes all handlers of given path
+func (w *Watcher) Unwatch(path string) {
+	delete(w.files, path)
+}
+
+// Watching returns whether path is watched
+func (w *Watcher) Watching(path string) bool {
+	_, ok := w.files[path]
+	return ok
+}
+
+// Poll checks files if period elapsed since last check, see Check
+func (w *Watcher) Poll() error {
+	if time.Since(w.last) < w.Period {
+		return nil
+	}
+	return w.Check()
+}
+
+// Check calls handlers of all files that changed since last check, first error is
+// returned though all handlers are called. File that was deleted is not considered
+// changed until it appears again.
+func (w *Watcher) Check() (err error) {
+	w.last = time.Now()
+	for path, f := range w.files {
+		mod, size := stat(path)
+		if mod.IsZero() || mod.Equal(f.mod) && size == f.size {
+			continue
+		}
+		f.mod, f.size = mod, size
+
+		for _, h := range f.handlers {
+			if er := h(path); er != nil && err == nil {
+				err = ErrReload.Args(path).Wrap(er)
+			}
+		}
+	}
+
+	return
+}
+
+// stat returns modification time and size of file, time is zero if file does not exist
+func stat(path string) (time.Time, int64) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, 0
+	}
+	return info.ModTime(), info.Size()
+}
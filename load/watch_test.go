@@ -0,0 +1,51 @@
+package load
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatcher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.goss")
+	if err := ioutil.WriteFile(path, []byte("a"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	w := NWatcher(0)
+	var calls int
+	w.Watch(path, func(p string) error {
+		calls++
+		return nil
+	})
+
+	future := time.Now().Add(time.Hour)
+
+	testCases := []struct {
+		desc  string
+		do    func()
+		calls int
+	}{
+		{"unchanged", func() {}, 0},
+		{"modified", func() { os.Chtimes(path, future, future) }, 1},
+		{"size", func() { ioutil.WriteFile(path, []byte("ab"), os.ModePerm) }, 2},
+		{"removed", func() { os.Remove(path) }, 2},
+		{"unwatched", func() {
+			w.Unwatch(path)
+			ioutil.WriteFile(path, []byte("abc"), os.ModePerm)
+		}, 2},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			tC.do()
+			if err := w.Poll(); err != nil {
+				t.Error(err)
+			}
+			if calls != tC.calls {
+				t.Errorf("\n%v\n%v", calls, tC.calls)
+			}
+		})
+	}
+}
@@ -3,6 +3,8 @@ package refl
 import (
 	"fmt"
 	"reflect"
+	"strconv"
+	"strings"
 
 	"github.com/jakubDoka/sterr"
 )
@@ -135,3 +137,105 @@ func Convert(scr, dest interface{}, method string) error {
 
 	return nil
 }
+
+// Path and Assign related errors
+var (
+	ErrNotTraversable = sterr.New("value of type %v has no field '%s'")
+	ErrIndex          = sterr.New("index '%s' is invalid for length %d")
+	ErrNilPath        = sterr.New("nil value at '%s'")
+	ErrAssign         = sterr.New("cannot assign %v to %v")
+)
+
+// Path walks value by dot separated path and returns value it points to, path can contain
+// struct fields, map keys (only maps with string keys) and slice or array indexes, pointers
+// and interfaces are dereferenced on the way, empty path returns dereferenced value
+//
+//	v, err := refl.Path(reflect.ValueOf(&game), "Players.0.Name")
+//
+// value is settable only if it is reachable trough pointer and does not come from map
+func Path(v reflect.Value, path string) (reflect.Value, error) {
+	v = deref(v)
+	if path == "" {
+		return v, nil
+	}
+
+	var traveled string
+	for _, name := range strings.Split(path, ".") {
+		if !v.IsValid() {
+			return v, ErrNilPath.Args(traveled)
+		}
+
+		switch v.Kind() {
+		case reflect.Struct:
+			f := v.FieldByName(name)
+			if !f.IsValid() {
+				return f, ErrNotTraversable.Args(v.Type(), name)
+			}
+			v = f
+		case reflect.Map:
+			if v.Type().Key().Kind() != reflect.String {
+				return v, ErrNotTraversable.Args(v.Type(), name)
+			}
+			v = v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+			if !v.IsValid() {
+				return v, ErrNilPath.Args(traveled + name)
+			}
+		case reflect.Slice, reflect.Array:
+			i, err := strconv.Atoi(name)
+			if err != nil || i < 0 || i >= v.Len() {
+				return v, ErrIndex.Args(name, v.Len())
+			}
+			v = v.Index(i)
+		default:
+			return v, ErrNotTraversable.Args(v.Type(), name)
+		}
+
+		v = deref(v)
+		traveled += name + "."
+	}
+
+	return v, nil
+}
+
+// Assign sets dest to src, if types differ, src is converted. Numbers convert between
+// each other, anything can be assigned to string as it is formatted by fmt and string is
+// parsed if dest is not string.
+func Assign(dest, src reflect.Value) error {
+	src = deref(src)
+	if !src.IsValid() {
+		dest.Set(reflect.Zero(dest.Type()))
+		return nil
+	}
+
+	st, dt := src.Type(), dest.Type()
+	switch {
+	case st.AssignableTo(dt):
+		dest.Set(src)
+	case dt.Kind() == reflect.String:
+		dest.SetString(fmt.Sprint(src.Interface()))
+	case st.Kind() == reflect.String:
+		tmp := reflect.New(dt)
+		_, err := fmt.Sscan(src.String(), tmp.Interface())
+		if err != nil {
+			return ErrAssign.Args(st, dt).Wrap(err)
+		}
+		dest.Set(tmp.Elem())
+	case numeric(st) && numeric(dt):
+		dest.Set(src.Convert(dt))
+	default:
+		return ErrAssign.Args(st, dt)
+	}
+
+	return nil
+}
+
+func numeric(t reflect.Type) bool {
+	return t.Kind() >= reflect.Int && t.Kind() <= reflect.Float64
+}
+
+func deref(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	return v
+}
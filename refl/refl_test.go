@@ -172,3 +172,75 @@ func TestF(t *testing.T) {
 	r, n := utf8.DecodeRuneInString("\n")
 	t.Error(r, n, '\n')
 }
+
+func TestPath(t *testing.T) {
+	type Player struct {
+		Name string
+		HP   *int
+	}
+	type Game struct {
+		Players []Player
+		Stats   map[string]float64
+		Current interface{}
+	}
+
+	hp := 10
+	g := Game{
+		Players: []Player{{"a", &hp}, {"b", nil}},
+		Stats:   map[string]float64{"time": 3},
+		Current: &Player{Name: "c"},
+	}
+
+	testCases := []struct {
+		desc, path string
+		res        interface{}
+		err        bool
+	}{
+		{desc: "index", path: "Players.1.Name", res: "b"},
+		{desc: "pointer", path: "Players.0.HP", res: 10},
+		{desc: "map", path: "Stats.time", res: 3.0},
+		{desc: "interface", path: "Current.Name", res: "c"},
+		{desc: "missing field", path: "Players.0.Age", err: true},
+		{desc: "out of bounds", path: "Players.2", err: true},
+		{desc: "nil", path: "Players.1.HP.X", err: true},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			v, err := Path(reflect.ValueOf(&g), tC.path)
+			if (err != nil) != tC.err {
+				t.Errorf("\n%v\n%v", err, tC.err)
+			}
+			if err == nil && v.Interface() != tC.res {
+				t.Errorf("\n%v\n%v", v.Interface(), tC.res)
+			}
+		})
+	}
+}
+
+func TestAssign(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		dest, src interface{}
+		err       bool
+	}{
+		{desc: "same", dest: "a", src: "a"},
+		{desc: "number", dest: 10, src: 10.5},
+		{desc: "format", dest: "10", src: 10},
+		{desc: "parse", dest: 10.5, src: "10.5"},
+		{desc: "parse bool", dest: true, src: "true"},
+		{desc: "invalid parse", dest: 0, src: "a", err: true},
+		{desc: "incompatible", dest: false, src: 1, err: true},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			dest := reflect.New(reflect.TypeOf(tC.dest)).Elem()
+			err := Assign(dest, reflect.ValueOf(tC.src))
+			if (err != nil) != tC.err {
+				t.Errorf("\n%v\n%v", err, tC.err)
+			}
+			if err == nil && dest.Interface() != tC.dest {
+				t.Errorf("\n%v\n%v", dest.Interface(), tC.dest)
+			}
+		})
+	}
+}